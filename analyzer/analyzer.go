@@ -2,6 +2,7 @@
 package analyzer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,14 +21,63 @@ var Analyzer = &analysis.Analyzer{
 	FactTypes: []analysis.Fact{
 		(*PackageEffectsFact)(nil),
 		(*FunctionEffectsFact)(nil),
+		(*TableNameFact)(nil),
+		(*EffectDefinitionsFact)(nil),
 	},
 }
 
+var (
+	// callGraphMode is the call graph backend selected with the -callgraph flag
+	callGraphMode = CallGraphSyntax
+	// strictMode is the treatment of unknown callees selected with the -strict flag
+	strictMode = StrictOff
+	// inferNames lists the effect inferers enabled with the -infer flag
+	inferNames = DefaultInferers
+	// operations is the operation hierarchy selected with the -operations flag
+	operations = DefaultOperations
+	// useStdlibCatalog enables the built-in catalog of standard library effects with the -stdlib flag
+	useStdlibCatalog = false
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&callGraphMode, "callgraph", CallGraphSyntax,
+		"backend resolving calls through interfaces and function values: syntax, cha or vta")
+	Analyzer.Flags.StringVar(&strictMode, "strict", StrictOff,
+		"treatment of calls whose callee's effects are unknown: report, top or registry")
+	Analyzer.Flags.StringVar(&inferNames, "infer", DefaultInferers,
+		"comma-separated effect inferers for library calls; empty disables inference")
+	Analyzer.Flags.StringVar(&operations, "operations", DefaultOperations,
+		"comma-separated operation hierarchy such as write=insert|update|delete; empty disables it")
+	Analyzer.Flags.BoolVar(&useStdlibCatalog, "stdlib", false,
+		"use the built-in catalog of standard library effects; the effect registry overrides its entries")
+}
+
 func run(pass *analysis.Pass) (any, error) {
 	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
 	// Create effect analysis
 	effectAnalysis := NewEffectAnalysis(pass, insp)
+	if !validCallGraphMode(callGraphMode) {
+		return nil, fmt.Errorf("invalid call graph backend %q: want syntax, cha or vta", callGraphMode)
+	}
+	effectAnalysis.CallGraphMode = callGraphMode
+	if !validStrictMode(strictMode) {
+		return nil, fmt.Errorf("invalid strict mode %q: want report, top or registry", strictMode)
+	}
+	effectAnalysis.StrictMode = strictMode
+	enabled, ok := selectInferers(inferNames)
+	if !ok {
+		return nil, fmt.Errorf("invalid inferers %q", inferNames)
+	}
+	effectAnalysis.Inferers = enabled
+	hierarchy, err := ParseOperationHierarchy(operations)
+	if err == nil {
+		effectAnalysis.Lattice, err = NewEffectLattice(hierarchy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid operation hierarchy %q: %v", operations, err)
+	}
+	effectAnalysis.Definitions.lattice = effectAnalysis.Lattice
 
 	// Detect if we're running under analysistest
 	// When running under analysistest, the package path might contain test patterns
@@ -75,20 +125,31 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 		// Silently ignore errors loading JSON
 	}
+	if useStdlibCatalog && !inStandardLibrary(pass) {
+		var err error
+		if jsonEffects, err = withStdlibCatalog(jsonEffects); err != nil {
+			return nil, fmt.Errorf("stdlib catalog: %w", err)
+		}
+	}
 	effectAnalysis.JSONEffects = jsonEffects
 	effectAnalysis.Resolver.SetJSONEffects(jsonEffects)
 
 	// Phase 0: Import effects from other packages via Facts
 	effectAnalysis.ImportAllPackageEffects()
 
-	// Phase 1: Collect all functions and their declared effects
+	// Phase 1: Collect named effect sets, then all functions and their declared effects
+	effectAnalysis.CollectDefinitions()
 	effectAnalysis.CollectFunctions()
+	effectAnalysis.CollectForbidden()
 
-	// Phase 2: Build call graph
+	// Phase 2: Build call graph, following function values to the functions they hold
+	effectAnalysis.BuildFuncValueFlow()
+	effectAnalysis.BuildSSACallGraph()
 	effectAnalysis.BuildCallGraph()
 
 	// Phase 2.5: Enhance with cross-package support
 	EnhanceWithCrossPackageSupportV3(effectAnalysis)
+	effectAnalysis.CollectUnknownCalls()
 
 	// Debug: Print package facts
 	effectAnalysis.debugPackageFacts()
@@ -100,10 +161,16 @@ func run(pass *analysis.Pass) (any, error) {
 	// Phase 4: Check effect consistency
 	effectAnalysis.debugCheckEffects()
 	effectAnalysis.CheckEffects()
+	effectAnalysis.CheckInterfaceImplementations()
+	effectAnalysis.CheckFuncContracts()
+	effectAnalysis.CheckForbidden()
+	effectAnalysis.CheckUnknownCalls()
 
 	// Phase 5: Export effects as Facts for dependent packages
 	if !effectAnalysis.DisableFacts {
 		effectAnalysis.ExportPackageEffects()
+		effectAnalysis.ExportTableNames()
+		effectAnalysis.ExportDefinitions()
 	}
 
 	return nil, nil
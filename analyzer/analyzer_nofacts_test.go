@@ -23,7 +23,7 @@ func TestAnalyzerWithoutFacts(t *testing.T) {
 	// Also set env var to ensure Facts are disabled
 	t.Setenv("DIRTY_DISABLE_FACTS", "1")
 	testdata := analysistest.TestData()
-	analysistest.Run(t, testdata, analyzerWithoutFacts, "basic", "complex", "implicit")
+	analysistest.Run(t, testdata, analyzerWithoutFacts, "basic", "complex", "implicit", "qualified", "interfaces", "polymorphic", "funclits", "functypes", "async", "generics", "funcrefs", "embedding", "initeffects")
 }
 
 func TestAnalyzerWithJSONEffectsWithoutFacts(t *testing.T) {
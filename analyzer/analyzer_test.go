@@ -9,7 +9,7 @@ import (
 
 func TestAnalyzer(t *testing.T) {
 	testdata := analysistest.TestData()
-	analysistest.Run(t, testdata, analyzer.Analyzer, "basic", "complex", "implicit")
+	analysistest.Run(t, testdata, analyzer.Analyzer, "basic", "complex", "implicit", "qualified", "interfaces", "polymorphic", "funclits", "functypes", "async", "generics", "funcrefs", "embedding", "initeffects")
 }
 
 func TestAnalyzerWithJSONEffects(t *testing.T) {
@@ -20,6 +20,162 @@ func TestAnalyzerWithJSONEffects(t *testing.T) {
 	analysistest.Run(t, testdata, analyzer.Analyzer, "jsoneffects")
 }
 
+func TestSQLInference(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "sqleffects")
+}
+
+func TestGormInference(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "gormeffects", "crossgorm/app")
+}
+
+func TestEntInference(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "enteffects")
+}
+
+func TestRedisInference(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "rediseffects")
+}
+
+func TestBrokerInference(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "brokereffects")
+}
+
+func TestEffectDefinitions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "definitions", "crossdefine/app", "crossdefine/alias")
+}
+
+func TestWildcards(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "wildcards")
+}
+
+func TestEffectHierarchy(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "hierarchy")
+}
+
+func TestForbiddenEffects(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "forbid", "forbidpkg")
+}
+
+func TestStdlibCatalog(t *testing.T) {
+	setFlag(t, "stdlib", "true")
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "stdlibeffects/app")
+}
+
+func TestCrossPackageMethodCalls(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "crossmethod/handler", "crossmethod/indirect")
+}
+
+func TestCrossPackageContracts(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "crosscontract/app")
+}
+
+func TestPackageInitEffects(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "crossinit/app")
+}
+
+func TestSSACallGraph(t *testing.T) {
+	setFlag(t, "callgraph", "vta")
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "ssacallgraph")
+}
+
+func TestCHACallGraph(t *testing.T) {
+	setFlag(t, "callgraph", "cha")
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "chacallgraph")
+}
+
+func TestSSACallGraphWithStdlib(t *testing.T) {
+	// Standard library packages are created from type information without building their bodies
+	for _, mode := range []string{"cha", "vta"} {
+		t.Run(mode, func(t *testing.T) {
+			setFlag(t, "callgraph", mode)
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, "ssafmt")
+		})
+	}
+}
+
+func TestSSACallGraphMatchesSyntax(t *testing.T) {
+	// Both backends give the same results where the syntactic one resolves the calls
+	setFlag(t, "callgraph", "vta")
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.Analyzer, "basic", "complex", "implicit", "qualified", "interfaces", "polymorphic", "funclits", "functypes", "async", "generics", "funcrefs", "embedding", "initeffects")
+}
+
+func TestStrictMode(t *testing.T) {
+	for mode, pkg := range map[string]string{
+		"report":   "strictreport",
+		"top":      "stricttop",
+		"registry": "strictregistry",
+	} {
+		t.Run(mode, func(t *testing.T) {
+			setFlag(t, "strict", mode)
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, pkg)
+		})
+	}
+}
+
+func TestStrictModeWithoutDeclarations(t *testing.T) {
+	for _, mode := range []string{"report", "top", "registry"} {
+		t.Run(mode, func(t *testing.T) {
+			setFlag(t, "strict", mode)
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, "strictgradual")
+		})
+	}
+}
+
+func TestStrictModeWithFacts(t *testing.T) {
+	// Facts are enabled for packages with a slash in their path
+	for mode, pkg := range map[string]string{
+		"report": "strictfacts/app",
+		"top":    "strictfacts/top",
+	} {
+		t.Run(mode, func(t *testing.T) {
+			setFlag(t, "strict", mode)
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, pkg)
+		})
+	}
+}
+
+func TestStrictModeWithIndirectImports(t *testing.T) {
+	// Callees known from the facts of indirectly imported packages have their effects
+	for _, mode := range []string{"report", "top"} {
+		t.Run(mode, func(t *testing.T) {
+			setFlag(t, "strict", mode)
+			testdata := analysistest.TestData()
+			analysistest.Run(t, testdata, analyzer.Analyzer, "strictfacts/indirect")
+		})
+	}
+}
+
+// setFlag sets a flag of the analyzer for the duration of a test
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	flag := analyzer.Analyzer.Flags.Lookup(name)
+	old := flag.Value.String()
+	if err := flag.Value.Set(value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = flag.Value.Set(old) })
+}
+
 func TestParseEffects(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,218 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// assignment is a value flowing into a location of a known static type
+type assignment struct {
+	Dst types.Type   // static type of the destination
+	Obj types.Object // destination variable or struct field, if known
+	Src ast.Expr     // assigned expression
+}
+
+// forEachAssignment calls fn for every assignment, variable declaration,
+// composite literal element, call argument, conversion, returned value and
+// channel send in the package
+func (ea *EffectAnalysis) forEachAssignment(fn func(a assignment)) {
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.SendStmt)(nil),
+	}
+
+	ea.Inspector.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// Operator assignments such as += never assign functions or interfaces
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				a := assignment{
+					Dst: ea.Pass.TypesInfo.TypeOf(lhs),
+					Obj: ea.assignedObject(lhs),
+					Src: n.Rhs[0],
+				}
+				if len(n.Lhs) == len(n.Rhs) {
+					a.Src = n.Rhs[i]
+				}
+				if a.Dst != nil {
+					fn(a)
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				obj := ea.Pass.TypesInfo.Defs[name]
+				if obj == nil || len(n.Values) == 0 {
+					continue
+				}
+				a := assignment{Dst: obj.Type(), Obj: obj, Src: n.Values[0]}
+				if len(n.Names) == len(n.Values) {
+					a.Src = n.Values[i]
+				}
+				fn(a)
+			}
+		case *ast.CompositeLit:
+			ea.forEachElement(n, fn)
+		case *ast.CallExpr:
+			ea.forEachArgument(n, fn)
+		case *ast.ReturnStmt:
+			ea.forEachResult(n, stack, fn)
+		case *ast.SendStmt:
+			if ch, ok := ea.Pass.TypesInfo.TypeOf(n.Chan).Underlying().(*types.Chan); ok {
+				fn(assignment{Dst: ch.Elem(), Src: n.Value})
+			}
+		}
+		return true
+	})
+}
+
+// forEachResult calls fn for every value of a return statement, with the result
+// of the enclosing function or function literal as destination
+func (ea *EffectAnalysis) forEachResult(ret *ast.ReturnStmt, stack []ast.Node, fn func(a assignment)) {
+	var sig *types.Signature
+	for i := len(stack) - 1; i >= 0 && sig == nil; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncLit:
+			sig, _ = ea.Pass.TypesInfo.TypeOf(f).(*types.Signature)
+		case *ast.FuncDecl:
+			if obj, ok := ea.Pass.TypesInfo.Defs[f.Name].(*types.Func); ok {
+				sig = obj.Type().(*types.Signature)
+			}
+		}
+	}
+	// Returns of multiple values of a call such as return f() are skipped
+	if sig == nil || sig.Results().Len() != len(ret.Results) {
+		return
+	}
+	for i, result := range ret.Results {
+		fn(assignment{Dst: sig.Results().At(i).Type(), Src: result})
+	}
+}
+
+// forEachArgument calls fn for every argument of a call, with the parameter
+// as destination, and for the operand of a conversion
+func (ea *EffectAnalysis) forEachArgument(call *ast.CallExpr, fn func(a assignment)) {
+	tv, ok := ea.Pass.TypesInfo.Types[call.Fun]
+	if !ok {
+		return
+	}
+	if tv.IsType() {
+		if len(call.Args) == 1 {
+			fn(assignment{Dst: tv.Type, Src: call.Args[0]})
+		}
+		return
+	}
+
+	sig, ok := tv.Type.Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	params := sig.Params()
+	for i, arg := range call.Args {
+		dst := argumentType(sig, call, i)
+		if dst == nil {
+			continue
+		}
+		a := assignment{Dst: dst, Src: arg}
+		if i < params.Len() && !(sig.Variadic() && i == params.Len()-1) {
+			a.Obj = params.At(i)
+		}
+		fn(a)
+	}
+}
+
+// argumentType returns the type of the parameter receiving the i-th argument of call
+func argumentType(sig *types.Signature, call *ast.CallExpr, i int) types.Type {
+	params := sig.Params()
+	switch {
+	case sig.Variadic() && i >= params.Len()-1:
+		dst := params.At(params.Len() - 1).Type()
+		if !call.Ellipsis.IsValid() {
+			dst = dst.(*types.Slice).Elem()
+		}
+		return dst
+	case i < params.Len():
+		return params.At(i).Type()
+	}
+	return nil
+}
+
+// forEachElement calls fn for every field value of a struct literal and every
+// element of a slice, array or map literal, and for the keys of map literals
+func (ea *EffectAnalysis) forEachElement(lit *ast.CompositeLit, fn func(a assignment)) {
+	tv, ok := ea.Pass.TypesInfo.Types[lit]
+	if !ok {
+		return
+	}
+	typ := tv.Type
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Struct:
+		for i, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if field, ok := ea.Pass.TypesInfo.Uses[key].(*types.Var); ok {
+					fn(assignment{Dst: field.Type(), Obj: field, Src: kv.Value})
+				}
+				continue
+			}
+			if i < t.NumFields() {
+				field := t.Field(i)
+				fn(assignment{Dst: field.Type(), Obj: field, Src: elt})
+			}
+		}
+	case *types.Slice:
+		forEachValue(lit, t.Elem(), fn)
+	case *types.Array:
+		forEachValue(lit, t.Elem(), fn)
+	case *types.Map:
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				fn(assignment{Dst: t.Key(), Src: kv.Key})
+				fn(assignment{Dst: t.Elem(), Src: kv.Value})
+			}
+		}
+	}
+}
+
+// forEachValue calls fn for every element of a slice or array literal, whose
+// elements may have indices as keys
+func forEachValue(lit *ast.CompositeLit, elem types.Type, fn func(a assignment)) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		fn(assignment{Dst: elem, Src: elt})
+	}
+}
+
+// assignedObject returns the variable or struct field assigned by lhs
+func (ea *EffectAnalysis) assignedObject(lhs ast.Expr) types.Object {
+	switch e := ast.Unparen(lhs).(type) {
+	case *ast.Ident:
+		return ea.Pass.TypesInfo.ObjectOf(e)
+	case *ast.SelectorExpr:
+		if sel, ok := ea.Pass.TypesInfo.Selections[e]; ok && sel.Kind() == types.FieldVal {
+			return sel.Obj()
+		}
+		// Package-level variable of another package
+		return ea.Pass.TypesInfo.Uses[e.Sel]
+	}
+	return nil
+}
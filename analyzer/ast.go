@@ -62,8 +62,193 @@ func (s *LiteralSet) String() string {
 	return fmt.Sprintf("{ %s }", strings.Join(parts, " | "))
 }
 
-// EffectRef represents a reference to a named effect (Phase 2)
-// e.g., userOps
+// Precedence of the set operators, from loosest to tightest: | < \ < &
+const (
+	precUnion = iota + 1
+	precDifference
+	precIntersection
+	precOperand
+)
+
+// DifferenceExpr represents the effects of Left that are not in Right
+// e.g., userOps \ { delete[users] }
+type DifferenceExpr struct {
+	Left  EffectExpr
+	Right EffectExpr
+}
+
+// Eval returns the effects of the left set that the right set does not cover, with
+// the wildcards, target paths and operation hierarchy of the resolver's lattice
+func (d *DifferenceExpr) Eval(resolver EffectResolver) (StringSet, error) {
+	left, err := d.Left.Eval(resolver)
+	if err != nil {
+		return nil, err
+	}
+	right, err := d.Right.Eval(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return latticeOf(resolver).Difference(left, right)
+}
+
+func (d *DifferenceExpr) String() string {
+	return fmt.Sprintf("%s \\ %s", operandString(d.Left, precDifference, false), operandString(d.Right, precDifference, true))
+}
+
+// IntersectionExpr represents the effects in both Left and Right
+// e.g., userOps & dbOps
+type IntersectionExpr struct {
+	Left  EffectExpr
+	Right EffectExpr
+}
+
+// Eval returns the effects both sets cover, with the wildcards, target paths and
+// operation hierarchy of the resolver's lattice
+func (i *IntersectionExpr) Eval(resolver EffectResolver) (StringSet, error) {
+	left, err := i.Left.Eval(resolver)
+	if err != nil {
+		return nil, err
+	}
+	right, err := i.Right.Eval(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return latticeOf(resolver).Intersection(left, right), nil
+}
+
+func (i *IntersectionExpr) String() string {
+	return fmt.Sprintf("%s & %s", operandString(i.Left, precIntersection, false), operandString(i.Right, precIntersection, true))
+}
+
+// precedence returns the precedence of the operator at the root of expr. Sets are
+// printed in braces, so they bind as tightly as labels and references.
+func precedence(expr EffectExpr) int {
+	switch expr.(type) {
+	case *DifferenceExpr:
+		return precDifference
+	case *IntersectionExpr:
+		return precIntersection
+	}
+	return precOperand
+}
+
+// operandString prints an operand of an operator of precedence prec, parenthesized
+// if it binds more loosely, or as loosely on the right as operators are left-associative
+func operandString(expr EffectExpr, prec int, right bool) string {
+	p := precedence(expr)
+	if p < prec || right && p == prec {
+		return "(" + expr.String() + ")"
+	}
+	return expr.String()
+}
+
+// AsyncDecl represents a declaration with an async clause
+// e.g., { insert[users] } async { network[mailer] }
+// The async set declares the effects of the goroutines spawned by the function,
+// separately from the effects of the function itself.
+type AsyncDecl struct {
+	Sync  EffectExpr
+	Async EffectExpr
+}
+
+// Eval returns the synchronous effects; the async set is evaluated separately
+func (d *AsyncDecl) Eval(resolver EffectResolver) (StringSet, error) {
+	return d.Sync.Eval(resolver)
+}
+
+func (d *AsyncDecl) String() string {
+	return fmt.Sprintf("%s async %s", d.Sync.String(), d.Async.String())
+}
+
+// EffectVar represents an effect variable bound to a function-typed parameter
+// e.g., e in { e | begin[tx] | commit[tx] } for func WithTx(ctx context.Context, e func() error)
+// The variable stands for the effects of the function passed as that parameter,
+// which are only known at each call site.
+type EffectVar struct {
+	Name string
+}
+
+// Eval returns an empty set because a variable has no concrete effects of its own
+func (v *EffectVar) Eval(_ EffectResolver) (StringSet, error) {
+	return NewStringSet(), nil
+}
+
+func (v *EffectVar) String() string {
+	return v.Name
+}
+
+// BindEffectVars replaces target-less labels whose names are in vars with effect variables
+func BindEffectVars(expr EffectExpr, vars map[string]bool) EffectExpr {
+	switch e := expr.(type) {
+	case *EffectLabel:
+		if e.Target == "" && vars[e.Operation] {
+			return &EffectVar{Name: e.Operation}
+		}
+	case *LiteralSet:
+		elements := make([]EffectExpr, len(e.Elements))
+		for i, elem := range e.Elements {
+			elements[i] = BindEffectVars(elem, vars)
+		}
+		return &LiteralSet{Elements: elements}
+	case *DifferenceExpr:
+		return &DifferenceExpr{Left: BindEffectVars(e.Left, vars), Right: BindEffectVars(e.Right, vars)}
+	case *IntersectionExpr:
+		return &IntersectionExpr{Left: BindEffectVars(e.Left, vars), Right: BindEffectVars(e.Right, vars)}
+	case *AsyncDecl:
+		return &AsyncDecl{Sync: BindEffectVars(e.Sync, vars), Async: BindEffectVars(e.Async, vars)}
+	}
+	return expr
+}
+
+// BindEffectRefs replaces target-less labels for which isRef reports true with
+// references to named effect sets
+func BindEffectRefs(expr EffectExpr, isRef func(name string) bool) EffectExpr {
+	switch e := expr.(type) {
+	case *EffectLabel:
+		if e.Target == "" && isRef(e.Operation) {
+			return &EffectRef{Name: e.Operation}
+		}
+	case *LiteralSet:
+		elements := make([]EffectExpr, len(e.Elements))
+		for i, elem := range e.Elements {
+			elements[i] = BindEffectRefs(elem, isRef)
+		}
+		return &LiteralSet{Elements: elements}
+	case *DifferenceExpr:
+		return &DifferenceExpr{Left: BindEffectRefs(e.Left, isRef), Right: BindEffectRefs(e.Right, isRef)}
+	case *IntersectionExpr:
+		return &IntersectionExpr{Left: BindEffectRefs(e.Left, isRef), Right: BindEffectRefs(e.Right, isRef)}
+	case *AsyncDecl:
+		return &AsyncDecl{Sync: BindEffectRefs(e.Sync, isRef), Async: BindEffectRefs(e.Async, isRef)}
+	}
+	return expr
+}
+
+// EffectVars returns the names of the effect variables used in expr
+func EffectVars(expr EffectExpr) StringSet {
+	result := NewStringSet()
+	switch e := expr.(type) {
+	case *EffectVar:
+		result.Add(e.Name)
+	case *LiteralSet:
+		for _, elem := range e.Elements {
+			result.AddAll(EffectVars(elem))
+		}
+	case *DifferenceExpr:
+		result.AddAll(EffectVars(e.Left))
+		result.AddAll(EffectVars(e.Right))
+	case *IntersectionExpr:
+		result.AddAll(EffectVars(e.Left))
+		result.AddAll(EffectVars(e.Right))
+	case *AsyncDecl:
+		result.AddAll(EffectVars(e.Sync))
+		result.AddAll(EffectVars(e.Async))
+	}
+	return result
+}
+
+// EffectRef represents a reference to a named effect set defined with //dirty-define:
+// e.g., userOps, or db.UserOps for a set exported by the imported package db
 type EffectRef struct {
 	Name string
 }
@@ -85,10 +270,10 @@ type EffectResolver interface {
 	Resolve(name string) (StringSet, error)
 }
 
-// NilResolver is a resolver that always returns an error
+// NilResolver is a resolver without any named effect sets
 type NilResolver struct{}
 
 // Resolve always returns an error for any name
 func (NilResolver) Resolve(name string) (StringSet, error) {
-	return nil, fmt.Errorf("effect reference '%s' not supported in Phase 1", name)
+	return nil, fmt.Errorf("undefined effect set %s", name)
 }
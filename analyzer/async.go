@@ -0,0 +1,73 @@
+package analyzer
+
+import "go/token"
+
+// separatesAsync reports whether the effects of goroutines spawned by fn are kept
+// apart from its own effects. Functions declared without an async clause make no
+// distinction: their goroutines' effects are checked against the declaration.
+func separatesAsync(fn *FunctionInfo) bool {
+	return !fn.HasDeclaration || fn.HasAsyncDeclaration
+}
+
+// callEffects returns the effects a call site contributes to the synchronous and
+// asynchronous effects of its caller fn. A call in a go statement contributes all of
+// the callee's effects as asynchronous; other calls contribute the callee's
+// asynchronous effects as asynchronous.
+func (ea *EffectAnalysis) callEffects(fn *FunctionInfo, call CallSite) (StringSet, StringSet, bool) {
+	effects, ok := ea.callSiteEffects(call)
+	if !ok {
+		return nil, nil, false
+	}
+
+	async := ea.Functions[call.Callee].AsyncEffects.Clone()
+	for _, name := range call.Instantiations {
+		if arg, ok := ea.Functions[name]; ok {
+			async.AddAll(arg.AsyncEffects)
+		}
+	}
+
+	sync := effects
+	if call.Kind == CallGo {
+		async.AddAll(effects)
+		sync = NewStringSet()
+	}
+	if !separatesAsync(fn) {
+		return sync.Union(async), NewStringSet(), true
+	}
+	return sync, async, true
+}
+
+// checkAsyncEffects reports a call site contributing asynchronous effects that are
+// not declared in the async clause of fn
+func (ea *EffectAnalysis) checkAsyncEffects(fn *FunctionInfo, call CallSite, async StringSet) {
+	if !fn.HasAsyncDeclaration || ea.Lattice.CoversAll(fn.DeclaredAsyncEffects, async) {
+		return
+	}
+	note := wildcardNote(fn.DeclaredAsyncEffects, ea.Lattice.Uncovered(fn.DeclaredAsyncEffects, async))
+
+	if call.Kind == CallGo {
+		ea.Pass.Reportf(call.Position,
+			"goroutine runs %s which has effects [%s] not declared in the async effects of this function%s",
+			ea.displayName(call.Callee), joinEffects(async.ToSlice()), note)
+		return
+	}
+	ea.Pass.Reportf(call.Position,
+		"function calls %s which has async effects [%s] not declared in this function%s",
+		ea.displayName(call.Callee), joinEffects(async.ToSlice()), note)
+}
+
+// applyAsyncDeclaration sets the declared async effects of info from the async
+// clause of its declaration, if any
+func (ea *EffectAnalysis) applyAsyncDeclaration(info *FunctionInfo, expr EffectExpr, pos token.Pos) {
+	decl, ok := expr.(*AsyncDecl)
+	if !ok {
+		return
+	}
+	effects, err := ea.evalDecl(decl.Async, pos)
+	if err != nil {
+		effects = NewStringSet()
+	}
+	info.HasAsyncDeclaration = true
+	info.DeclaredAsyncEffects = effects
+	info.AsyncEffects = effects.Clone()
+}
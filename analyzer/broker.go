@@ -0,0 +1,304 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// Import paths of the message broker clients
+const (
+	kafkaGoPackage = "github.com/segmentio/kafka-go"
+	natsPackage    = "github.com/nats-io/nats.go"
+)
+
+// saramaPackages lists the import paths of sarama, before and after its move to IBM
+var saramaPackages = []string{
+	"github.com/IBM/sarama",
+	"github.com/Shopify/sarama",
+}
+
+// natsPublishers and natsSubscribers list the methods of NATS connections and
+// JetStream contexts that publish to and subscribe to their subject
+var (
+	natsPublishers = map[string]bool{
+		"Publish": true, "PublishMsg": true, "PublishRequest": true, "PublishAsync": true,
+		"PublishMsgAsync": true, "Request": true, "RequestWithContext": true, "RequestMsg": true,
+	}
+	natsSubscribers = map[string]bool{
+		"Subscribe": true, "SubscribeSync": true, "QueueSubscribe": true, "QueueSubscribeSync": true,
+		"ChanSubscribe": true, "ChanQueueSubscribe": true, "QueueSubscribeSyncWithChan": true,
+		"PullSubscribe": true,
+	}
+)
+
+// kafkaInferer infers publish and consume effects from the Kafka clients kafka-go
+// and sarama, with the topic as target
+type kafkaInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (kafkaInferer) Name() string { return "kafka" }
+
+// Infer returns the effects of writing messages and of creating readers with kafka-go,
+// and of sending messages and consuming topics with sarama
+func (kafkaInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	args := ea.callArgs(call)
+	switch {
+	case inPackages(callee, kafkaGoPackage):
+		switch callee.Name() {
+		case "WriteMessages":
+			// The topic of the writer, or of each message for writers without one
+			sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			if !ok || len(args) == 0 || !isNamed(callee.Type().(*types.Signature).Recv(), "Writer") {
+				return nil, false
+			}
+			if topics, ok := ea.fieldStrings(sel.X, "Topic"); ok && len(topics) > 0 {
+				return brokerEffects("publish", topics)
+			}
+			var topics []string
+			for _, arg := range args[1:] {
+				topic, ok := ea.fieldStrings(arg, "Topic")
+				if !ok || len(topic) == 0 {
+					return nil, false
+				}
+				topics = append(topics, topic...)
+			}
+			return brokerEffects("publish", topics)
+		case "NewReader":
+			if len(args) != 1 {
+				return nil, false
+			}
+			topics, ok := ea.fieldStrings(args[0], "Topic")
+			if !ok || len(topics) == 0 {
+				topics, ok = ea.fieldStringLists(args[0], "GroupTopics")
+			}
+			if !ok {
+				return nil, false
+			}
+			return brokerEffects("consume", topics)
+		}
+	case inPackages(callee, saramaPackages...):
+		switch callee.Name() {
+		case "SendMessage", "SendMessages":
+			if len(args) != 1 {
+				return nil, false
+			}
+			var topics []string
+			for _, msg := range ea.elements(args[0]) {
+				topic, ok := ea.fieldStrings(msg, "Topic")
+				if !ok || len(topic) == 0 {
+					return nil, false
+				}
+				topics = append(topics, topic...)
+			}
+			return brokerEffects("publish", topics)
+		case "ConsumePartition":
+			topic, ok := ea.stringArg(call, callee, "topic")
+			if !ok {
+				return nil, false
+			}
+			return brokerEffects("consume", []string{topic})
+		case "Consume":
+			params := callee.Type().(*types.Signature).Params()
+			for i := 0; i < len(args) && i < params.Len(); i++ {
+				if params.At(i).Name() == "topics" {
+					topics, ok := ea.constStrings(ea.elements(args[i]))
+					if !ok {
+						return nil, false
+					}
+					return brokerEffects("consume", topics)
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// natsInferer infers publish and consume effects from NATS and JetStream, with the
+// subject as target
+type natsInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (natsInferer) Name() string { return "nats" }
+
+// Infer returns the effects of publishing to and subscribing to a subject given as a
+// constant, or as the Subject of a message literal
+func (natsInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	if !inPackages(callee, natsPackage, natsPackage+"/") {
+		return nil, false
+	}
+	op := ""
+	switch {
+	case natsPublishers[callee.Name()]:
+		op = "publish"
+	case natsSubscribers[callee.Name()]:
+		op = "consume"
+	default:
+		return nil, false
+	}
+
+	if subject, ok := ea.stringArg(call, callee, "subj", "subject"); ok {
+		return brokerEffects(op, []string{subject})
+	}
+	// Messages carry their subject: PublishMsg(&nats.Msg{Subject: "orders"})
+	for _, arg := range ea.callArgs(call) {
+		if subjects, ok := ea.fieldStrings(arg, "Subject"); ok && len(subjects) > 0 {
+			return brokerEffects(op, subjects)
+		}
+	}
+	return nil, false
+}
+
+// brokerEffects returns the effects of an operation on topics, unless a topic is not
+// a valid effect target, such as the NATS wildcard subject "orders.*"
+func brokerEffects(op string, topics []string) (StringSet, bool) {
+	if len(topics) == 0 {
+		return nil, false
+	}
+	effects := NewStringSet()
+	for _, topic := range topics {
+		if !validTarget(topic) {
+			return nil, false
+		}
+		effects.Add(op + "[" + topic + "]")
+	}
+	return effects, true
+}
+
+// isNamed reports whether the type of a receiver, or the type it points to, is named name
+func isNamed(recv *types.Var, name string) bool {
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == name
+}
+
+// fieldStrings returns the constant values of a string field of the struct values an
+// expression may be: a struct literal, or a variable or field whose every assignment
+// in the package is a struct literal. Literals without the field have no value for it.
+func (ea *EffectAnalysis) fieldStrings(expr ast.Expr, field string) ([]string, bool) {
+	lits, ok := ea.structLits(expr)
+	if !ok {
+		return nil, false
+	}
+	var values []string
+	for _, lit := range lits {
+		value, ok := litField(lit, field)
+		if !ok {
+			continue
+		}
+		strs, ok := ea.constStrings([]ast.Expr{value})
+		if !ok {
+			return nil, false
+		}
+		values = append(values, strs...)
+	}
+	return values, true
+}
+
+// fieldStringLists returns the constant elements of a string slice field of the
+// struct values an expression may be, as fieldStrings does for string fields
+func (ea *EffectAnalysis) fieldStringLists(expr ast.Expr, field string) ([]string, bool) {
+	lits, ok := ea.structLits(expr)
+	if !ok {
+		return nil, false
+	}
+	var values []string
+	for _, lit := range lits {
+		value, ok := litField(lit, field)
+		if !ok {
+			continue
+		}
+		strs, ok := ea.constStrings(ea.elements(value))
+		if !ok {
+			return nil, false
+		}
+		values = append(values, strs...)
+	}
+	return values, true
+}
+
+// litField returns the value of a field given by name in a struct literal
+func litField(lit *ast.CompositeLit, field string) (ast.Expr, bool) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// constStrings returns the values of string constants
+func (ea *EffectAnalysis) constStrings(exprs []ast.Expr) ([]string, bool) {
+	values := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		tv, ok := ea.Pass.TypesInfo.Types[expr]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return nil, false
+		}
+		values = append(values, constant.StringVal(tv.Value))
+	}
+	return values, true
+}
+
+// elements returns the elements of a slice literal, or the expression itself otherwise
+func (ea *EffectAnalysis) elements(expr ast.Expr) []ast.Expr {
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return []ast.Expr{expr}
+	}
+	if _, ok := ea.Pass.TypesInfo.TypeOf(lit).Underlying().(*types.Slice); !ok {
+		return []ast.Expr{expr}
+	}
+	return lit.Elts
+}
+
+// structLits returns the struct literals an expression may evaluate to
+func (ea *EffectAnalysis) structLits(expr ast.Expr) ([]*ast.CompositeLit, bool) {
+	if lit, ok := compositeLit(expr); ok {
+		return []*ast.CompositeLit{lit}, true
+	}
+	obj := ea.assignedObject(expr)
+	if obj == nil {
+		return nil, false
+	}
+
+	if ea.objectLits == nil {
+		// Assignments of other expressions are recorded as nil
+		ea.objectLits = make(map[types.Object][]*ast.CompositeLit)
+		ea.forEachAssignment(func(a assignment) {
+			if a.Obj == nil {
+				return
+			}
+			lit, _ := compositeLit(a.Src)
+			ea.objectLits[varOrigin(a.Obj)] = append(ea.objectLits[varOrigin(a.Obj)], lit)
+		})
+	}
+	lits := ea.objectLits[varOrigin(obj)]
+	for _, lit := range lits {
+		if lit == nil {
+			return nil, false
+		}
+	}
+	return lits, len(lits) > 0
+}
+
+// compositeLit returns the composite literal of an expression such as T{...} or &T{...}
+func compositeLit(expr ast.Expr) (*ast.CompositeLit, bool) {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	return lit, ok
+}
@@ -2,8 +2,6 @@ package analyzer
 
 import (
 	"go/ast"
-	"path/filepath"
-	"strings"
 )
 
 // EnhanceWithCrossPackageSupportV3 adds cross-package analysis capabilities using Facts
@@ -13,76 +11,46 @@ func EnhanceWithCrossPackageSupportV3(ea *EffectAnalysis) {
 	if ea.DisableFacts {
 		return
 	}
-	// Analyze imports for all files
-	imports := make(map[string]string)
-	for _, file := range ea.Pass.Files {
-		for _, imp := range file.Imports {
-			path := strings.Trim(imp.Path.Value, `"`)
-			alias := ""
-			if imp.Name != nil {
-				alias = imp.Name.Name
-			} else {
-				// Use the last component of the path as the alias
-				alias = filepath.Base(path)
-			}
-			imports[alias] = path
-		}
-	}
 
 	// Re-analyze function bodies to find cross-package calls
 	for funcName, info := range ea.Functions {
-		if info.Decl == nil {
-			continue
-		}
-		ast.Inspect(info.Decl, func(n ast.Node) bool {
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
+		ea.inspectCalls(info, func(call *ast.CallExpr, kind CallKind) {
+			// Resolve the callee through type information. This covers package-level
+			// functions (pkg.Func) as well as methods called on values, pointers and
+			// embedded fields whose types are declared in other packages.
+			callee := ea.calleeFunc(call)
+			if callee == nil || callee.Pkg() == nil || callee.Pkg() == ea.Pass.Pkg {
+				return
 			}
+			// Interface calls resolved by the SSA backend and calls with inferred
+			// effects are in the call graph already
+			if _, ok := ea.dynamicTargets(info, call); ok {
+				return
+			}
+			if _, ok := ea.inferEffects(call); ok {
+				return
+			}
+			resolvedName := FuncKey(callee)
 
-			// Resolve cross-package calls
-			if fun, ok := call.Fun.(*ast.SelectorExpr); ok {
-				if ident, ok := fun.X.(*ast.Ident); ok {
-					// Check if it's an imported package
-					if pkgPath, isImport := imports[ident.Name]; isImport {
-						// It's an imported package function
-						resolvedName := pkgPath + "." + fun.Sel.Name
-
-						// Use UnifiedEffectResolver to get effects
-						effects, source := ea.Resolver.ResolveEffects(resolvedName)
-
-						// If not found with full path, try just function name for backward compatibility
-						if source == SourceUnknown {
-							effects, source = ea.Resolver.ResolveEffects(fun.Sel.Name)
-						}
-
-						// Add to call graph
-						ea.CallGraph.AddCall(funcName, resolvedName, call.Pos())
-
-						// Create a synthetic function info if we found effects
-						if source != SourceUnknown {
-							if _, exists := ea.Functions[resolvedName]; !exists {
-								ea.Functions[resolvedName] = &FunctionInfo{
-									Name:            resolvedName,
-									Package:         pkgPath,
-									DeclaredEffects: effects,
-									ComputedEffects: effects,
-									HasDeclaration:  true, // Treat as declared since it's from Facts/JSON
-									CallSites:       []CallSite{},
-								}
-							}
+			// Add to call graph
+			ea.CallGraph.AddCallSite(funcName, CallSite{Callee: resolvedName, Position: call.Pos(), Kind: kind})
 
-							// IMPORTANT: Add this cross-package call to the current function's call sites
-							info.CallSites = append(info.CallSites, CallSite{
-								Callee:   resolvedName,
-								Position: call.Pos(),
-							})
-						}
-					}
-				}
+			// Use Facts or JSON declarations to get effects
+			calleeInfo, ok := ea.importedFunction(callee)
+			if !ok {
+				return
 			}
 
-			return true
+			// IMPORTANT: Add this cross-package call to the current function's call sites
+			_, viaTypeParam := ea.typeParamRecv(call)
+			info.CallSites = append(info.CallSites, CallSite{
+				Callee:          resolvedName,
+				Position:        call.Pos(),
+				Kind:            kind,
+				Instantiations:  ea.instantiateEffectParams(funcName, calleeInfo, call),
+				TypeParamMethod: viaTypeParam,
+				Promoted:        ea.promotedThrough(call),
+			})
 		})
 	}
 }
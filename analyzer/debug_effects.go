@@ -18,20 +18,18 @@ func (ea *EffectAnalysis) debugCheckEffects() {
 		debugLog("Checking function: %s", fname)
 		debugLog("  Declared effects: %v", fn.DeclaredEffects.ToSlice())
 		debugLog("  Computed effects: %v", fn.ComputedEffects.ToSlice())
+		if len(fn.AsyncEffects) > 0 {
+			debugLog("  Async effects: %v", fn.AsyncEffects.ToSlice())
+		}
 		debugLog("  Call sites: %d", len(fn.CallSites))
 
 		for _, call := range fn.CallSites {
-			debugLog("    Call to: %s at %v", call.Callee, call.Position)
-			if callee, ok := ea.Functions[call.Callee]; ok {
-				debugLog("      Callee effects: %v", callee.ComputedEffects.ToSlice())
+			debugLog("    Call to: %s at %v (%s)", call.Callee, call.Position, call.Kind)
+			if calleeEffects, ok := ea.callSiteEffects(call); ok {
+				debugLog("      Callee effects: %v", calleeEffects.ToSlice())
 
 				// Check if effects are missing
-				missingEffects := NewStringSet()
-				for effect := range callee.ComputedEffects {
-					if !fn.DeclaredEffects.Contains(effect) {
-						missingEffects.Add(effect)
-					}
-				}
+				missingEffects := ea.Lattice.Uncovered(fn.DeclaredEffects, calleeEffects)
 
 				if len(missingEffects) > 0 {
 					debugLog("      MISSING EFFECTS: %v", missingEffects.ToSlice())
@@ -25,7 +25,7 @@ func (ea *EffectAnalysis) debugPropagateEffects() {
 		if len(fn.CallSites) > 0 {
 			debugLog("  %s calls:", caller)
 			for _, call := range fn.CallSites {
-				debugLog("    -> %s", call.Callee)
+				debugLog("    -> %s (%s)", call.Callee, call.Kind)
 			}
 		}
 	}
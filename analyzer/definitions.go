@@ -0,0 +1,315 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EffectDefinition is a named effect set defined by a //dirty-define: comment
+type EffectDefinition struct {
+	Name string
+	Expr EffectExpr
+	Pos  token.Pos
+}
+
+// EffectDefinitions resolves the names of the effect sets defined in the package
+// and exported by imported packages. It implements EffectResolver.
+type EffectDefinitions struct {
+	// local holds the definitions of the package
+	local map[string]*EffectDefinition
+	// imported holds the sets exported by imported packages, by the name the package
+	// is imported as and set name
+	imported map[string]map[string]StringSet
+
+	// values caches the evaluated definitions, and errors the failed ones
+	values map[string]StringSet
+	errors map[string]error
+	// evaluating is the chain of definitions being evaluated, to find cycles
+	evaluating []*EffectDefinition
+	// reported holds the definitions whose errors were reported
+	reported map[*EffectDefinition]bool
+	// lattice evaluates the set operators of definitions and declarations
+	lattice *EffectLattice
+}
+
+// NewEffectDefinitions creates an empty set of definitions
+func NewEffectDefinitions() *EffectDefinitions {
+	return &EffectDefinitions{
+		local:    make(map[string]*EffectDefinition),
+		imported: make(map[string]map[string]StringSet),
+		values:   make(map[string]StringSet),
+		errors:   make(map[string]error),
+		reported: make(map[*EffectDefinition]bool),
+	}
+}
+
+// definitionError is an error in the evaluation of a named effect set. Def is the
+// definition whose expression contains the error, nil for errors in declarations.
+type definitionError struct {
+	Def *EffectDefinition
+	Msg string
+}
+
+func (e *definitionError) Error() string {
+	return e.Msg
+}
+
+// Resolve returns the effects of the named set: "userOps" for a set of this package,
+// "db.UserOps" for a set exported by the imported package db
+func (d *EffectDefinitions) Resolve(name string) (StringSet, error) {
+	if pkgName, setName, ok := strings.Cut(name, "."); ok {
+		if set, ok := d.imported[pkgName][setName]; ok {
+			return set, nil
+		}
+		return nil, d.errorf("undefined effect set %s", name)
+	}
+
+	def, ok := d.local[name]
+	if !ok {
+		if similar, ok := d.similarName(name); ok {
+			return nil, d.errorf("undefined effect set %s (did you mean %s?)", name, similar)
+		}
+		return nil, d.errorf("undefined effect set %s", name)
+	}
+	if set, ok := d.values[name]; ok {
+		return set, nil
+	}
+	if err, ok := d.errors[name]; ok {
+		return nil, err
+	}
+	for i, other := range d.evaluating {
+		if other != def {
+			continue
+		}
+		chain := make([]string, 0, len(d.evaluating)-i+1)
+		for _, def := range d.evaluating[i:] {
+			chain = append(chain, def.Name)
+		}
+		chain = append(chain, name)
+		return nil, &definitionError{Def: def, Msg: fmt.Sprintf(
+			"effect set %s is defined in terms of itself: %s", name, strings.Join(chain, " -> "))}
+	}
+
+	d.evaluating = append(d.evaluating, def)
+	set, err := d.Bind(def.Expr).Eval(d)
+	d.evaluating = d.evaluating[:len(d.evaluating)-1]
+	if _, ok := err.(*definitionError); err != nil && !ok {
+		// Errors of the set operators belong to the definition using them
+		err = &definitionError{Def: def, Msg: err.Error()}
+	}
+	if err != nil {
+		d.errors[name] = err
+		return nil, err
+	}
+	d.values[name] = set
+	return set, nil
+}
+
+// Lattice returns the lattice evaluating the set operators
+func (d *EffectDefinitions) Lattice() *EffectLattice {
+	return d.lattice
+}
+
+// errorf returns an error found in the definition being evaluated, if any
+func (d *EffectDefinitions) errorf(format string, args ...any) error {
+	err := &definitionError{Msg: fmt.Sprintf(format, args...)}
+	if len(d.evaluating) > 0 {
+		err.Def = d.evaluating[len(d.evaluating)-1]
+	}
+	return err
+}
+
+// Bind replaces the target-less labels of expr that name effect sets with references
+func (d *EffectDefinitions) Bind(expr EffectExpr) EffectExpr {
+	return BindEffectRefs(expr, d.isRef)
+}
+
+// isRef reports whether a target-less label names an effect set: a set defined in
+// the package, a name qualified with a package name, a capitalized name as
+// exported sets are, or a misspelling of a set defined in the package. Other names
+// shadow no set and are labels such as network.
+func (d *EffectDefinitions) isRef(name string) bool {
+	if _, ok := d.local[name]; ok {
+		return true
+	}
+	if strings.Contains(name, ".") {
+		// Qualified names, including the ones of packages that are not imported
+		return true
+	}
+	if _, ok := d.similarName(name); ok {
+		return true
+	}
+	return isExportedName(name)
+}
+
+// similarName returns the set defined in the package whose name is closest to an
+// undefined name, if name is likely a misspelling of it: at most two edits away,
+// and not shorter than four letters so that short labels are not mistaken for sets
+func (d *EffectDefinitions) similarName(name string) (string, bool) {
+	if len(name) < 4 {
+		return "", false
+	}
+	best, bestDist := "", 3
+	for defined := range d.local {
+		dist := editDistance(name, defined)
+		if dist < bestDist || dist == bestDist && defined < best {
+			best, bestDist = defined, dist
+		}
+	}
+	return best, best != "" && bestDist <= 2
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Exported returns the evaluated sets with capitalized names, which other packages
+// may refer to
+func (d *EffectDefinitions) Exported() map[string][]string {
+	result := make(map[string][]string)
+	for name := range d.local {
+		if !isExportedName(name) {
+			continue
+		}
+		if set, err := d.Resolve(name); err == nil {
+			result[name] = set.ToSlice()
+		}
+	}
+	return result
+}
+
+// isExportedName reports whether a name starts with an upper-case letter
+func isExportedName(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// CollectDefinitions collects the //dirty-define: comments of the package and the
+// sets exported by imported packages, and reports invalid, duplicate, cyclic and
+// undefined definitions
+func (ea *EffectAnalysis) CollectDefinitions() {
+	d := ea.Definitions
+	for _, file := range ea.Pass.Files {
+		for _, spec := range file.Imports {
+			// Sets are qualified with the name the file imports the package as
+			pkgName := ea.Pass.TypesInfo.PkgNameOf(spec)
+			if pkgName == nil || pkgName.Name() == "_" || pkgName.Name() == "." {
+				continue
+			}
+			if _, ok := d.imported[pkgName.Name()]; ok {
+				continue
+			}
+			sets := make(map[string]StringSet)
+			d.imported[pkgName.Name()] = sets
+			var fact EffectDefinitionsFact
+			if ea.DisableFacts || !ea.Pass.ImportPackageFact(pkgName.Imported(), &fact) {
+				continue
+			}
+			for name, effects := range fact.Definitions {
+				sets[name] = NewStringSetFromSlice(effects)
+			}
+		}
+	}
+
+	for _, file := range ea.Pass.Files {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				text := strings.TrimSpace(comment.Text)
+				if !strings.HasPrefix(text, "//dirty-define:") && !strings.HasPrefix(text, "// dirty-define:") {
+					continue
+				}
+				name, expr, err := ParseEffectDefinition(text)
+				if err != nil {
+					ea.Pass.Reportf(comment.Pos(), "invalid effect set definition: %v", err)
+					continue
+				}
+				if _, ok := d.local[name]; ok {
+					ea.Pass.Reportf(comment.Pos(), "effect set %s is already defined", name)
+					continue
+				}
+				d.local[name] = &EffectDefinition{Name: name, Expr: expr, Pos: comment.Pos()}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(d.local))
+	for name := range d.local {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := d.Resolve(name); err != nil {
+			ea.reportDefinitionError(d.local[name].Pos, err)
+		}
+	}
+}
+
+// evalDecl evaluates a declaration after resolving the names of effect sets. Errors
+// in the names are reported at pos, or at the definition containing them.
+func (ea *EffectAnalysis) evalDecl(expr EffectExpr, pos token.Pos) (StringSet, error) {
+	effects, err := expr.Eval(ea.Definitions)
+	if err != nil {
+		ea.reportDefinitionError(pos, err)
+	}
+	return effects, err
+}
+
+// reportDefinitionError reports an error in the names of effect sets or in the set
+// operators once
+func (ea *EffectAnalysis) reportDefinitionError(pos token.Pos, err error) {
+	derr, ok := err.(*definitionError)
+	if !ok {
+		derr = &definitionError{Msg: err.Error()}
+	}
+	if derr.Def != nil {
+		if ea.Definitions.reported[derr.Def] {
+			return
+		}
+		ea.Definitions.reported[derr.Def] = true
+		pos = derr.Def.Pos
+	}
+	if pos.IsValid() {
+		ea.Pass.Reportf(pos, "%s", derr.Msg)
+	}
+}
+
+// EffectDefinitionsFact holds the named effect sets exported by a package, evaluated
+type EffectDefinitionsFact struct {
+	Definitions map[string][]string
+}
+
+// AFact marks EffectDefinitionsFact as a fact type
+func (*EffectDefinitionsFact) AFact() {}
+
+// String returns a human-readable representation of the definitions
+func (f *EffectDefinitionsFact) String() string {
+	return fmt.Sprintf("EffectDefinitionsFact{%d definitions}", len(f.Definitions))
+}
+
+// ExportDefinitions exports the named effect sets of the package with capitalized names
+func (ea *EffectAnalysis) ExportDefinitions() {
+	if exported := ea.Definitions.Exported(); len(exported) > 0 {
+		ea.Pass.ExportPackageFact(&EffectDefinitionsFact{Definitions: exported})
+	}
+}
@@ -1,8 +1,12 @@
 package analyzer
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"os"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/inspector"
@@ -23,6 +27,39 @@ type EffectAnalysis struct {
 
 	// UnifiedEffectResolver provides unified effect resolution
 	Resolver *UnifiedEffectResolver
+	// Definitions resolves the names of effect sets defined with //dirty-define:
+	Definitions *EffectDefinitions
+	// Lattice decides which declared effects cover the effects of callees
+	Lattice *EffectLattice
+
+	// FuncVars holds the functions that variables and struct fields may refer to
+	FuncVars map[types.Object]StringSet
+	// FuncResults holds the functions that each function may return
+	FuncResults map[string]StringSet
+
+	// funcLits maps function literals to their function info names
+	funcLits map[*ast.FuncLit]string
+	// fieldKeys caches the contract names of struct fields
+	fieldKeys map[*types.Var]string
+
+	// CallGraphMode selects how dynamic calls are resolved: syntax, cha or vta
+	CallGraphMode string
+	// Inferers infer the effects of calls of library functions
+	Inferers []EffectInferer
+	// inferred caches the inferred effects of calls, nil for calls without any
+	inferred map[*ast.CallExpr]StringSet
+	// objectLits holds the struct literals assigned to variables and fields for inferers,
+	// with nil for other assignments. It is built on first use.
+	objectLits map[types.Object][]*ast.CompositeLit
+
+	// StrictMode selects how calls of callees with unknown effects are treated
+	StrictMode string
+
+	// ssaCallees holds the targets of dynamic calls found by the SSA backend,
+	// by the position of the call's opening parenthesis
+	ssaCallees map[token.Pos]StringSet
+	// importedPkgs holds the paths of the packages whose Facts are in the resolver
+	importedPkgs map[string]bool
 }
 
 // NewEffectAnalysis creates a new EffectAnalysis
@@ -33,6 +70,17 @@ func NewEffectAnalysis(pass *analysis.Pass, inspect *inspector.Inspector) *Effec
 		Functions: make(map[string]*FunctionInfo),
 		CallGraph: NewCallGraph(),
 		Resolver:  NewUnifiedEffectResolver(),
+
+		Definitions: NewEffectDefinitions(),
+		Lattice:     &EffectLattice{implied: make(map[string]StringSet)},
+
+		FuncVars:    make(map[types.Object]StringSet),
+		FuncResults: make(map[string]StringSet),
+		funcLits:    make(map[*ast.FuncLit]string),
+		fieldKeys:   make(map[*types.Var]string),
+		inferred:    make(map[*ast.CallExpr]StringSet),
+
+		importedPkgs: make(map[string]bool),
 	}
 }
 
@@ -40,114 +88,275 @@ func NewEffectAnalysis(pass *analysis.Pass, inspect *inspector.Inspector) *Effec
 func (ea *EffectAnalysis) CollectFunctions() {
 	nodeFilter := []ast.Node{
 		(*ast.FuncDecl)(nil),
+		(*ast.GenDecl)(nil),
 	}
 
+	initCount := 0
 	ea.Inspector.Preorder(nodeFilter, func(n ast.Node) {
+		if decl, ok := n.(*ast.GenDecl); ok {
+			if decl.Tok != token.TYPE {
+				return
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				// The doc of an unparenthesized declaration is attached to the GenDecl
+				doc := spec.Doc
+				if doc == nil && !decl.Lparen.IsValid() {
+					doc = decl.Doc
+				}
+				ea.collectInterfaceMethods(spec)
+				ea.collectFuncTypes(spec, doc)
+			}
+			return
+		}
+
 		fn := n.(*ast.FuncDecl)
 		if fn.Name == nil {
 			return
 		}
+		obj, ok := ea.Pass.TypesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			return
+		}
+
+		funcName := FuncKey(obj)
+		if fn.Recv == nil && fn.Name.Name == "init" {
+			// A package may declare any number of init functions
+			initCount++
+			funcName = fmt.Sprintf("%s#%d", funcName, initCount)
+		}
 
-		funcName := fn.Name.Name
 		info := &FunctionInfo{
 			Name:            funcName,
 			Package:         ea.Pass.Pkg.Path(),
 			DeclaredEffects: NewStringSet(),
 			ComputedEffects: NewStringSet(),
 			HasDeclaration:  false,
+			Object:          obj,
 			Decl:            fn,
 			CallSites:       []CallSite{},
 		}
-
-		// Extract effects from // dirty: comment
-		if fn.Doc != nil {
-			for _, comment := range fn.Doc.List {
-				if effects := ParseEffects(comment.Text); effects != nil {
-					info.HasDeclaration = true
-					info.DeclaredEffects = NewStringSet(effects...)
-					info.ComputedEffects = NewStringSet(effects...)
-					break
-				}
-			}
-		}
+		ea.applyDeclaration(info, fn.Doc)
 
 		ea.Functions[funcName] = info
 		ea.Resolver.AddLocalFunction(funcName, info)
 	})
+
+	ea.collectInit()
+	ea.collectFuncLits()
+}
+
+// applyDeclaration sets the declared effects of info from its // dirty: comment,
+// falling back to JSON declarations; source code declarations take priority.
+// Functions with a malformed comment are left undeclared.
+func (ea *EffectAnalysis) applyDeclaration(info *FunctionInfo, doc *ast.CommentGroup) {
+	expr, ok := ea.parseDocDecl(doc)
+	if !ok && declComment(doc) == nil {
+		expr, ok = ea.Resolver.ResolveJSONExpr(info.Name, info.Package)
+	}
+	if !ok {
+		return
+	}
+
+	// Errors in the names of effect sets are reported at the comment, or at the
+	// function for JSON declarations
+	pos := token.NoPos
+	if comment := declComment(doc); comment != nil {
+		pos = comment.Pos()
+	} else if node := info.Node(); node != nil {
+		pos = node.Pos()
+	}
+
+	expr = ea.Definitions.Bind(ea.bindEffectParams(info, expr))
+	effects, err := ea.evalDecl(expr, pos)
+	if err != nil {
+		effects = NewStringSet()
+	}
+
+	info.HasDeclaration = true // JSON declarations are treated as declarations too
+	info.DeclaredEffects = effects
+	info.ComputedEffects = effects.Clone()
+	ea.applyAsyncDeclaration(info, expr, pos)
+}
+
+// parseDocDecl returns the effect expression of the first // dirty: comment in doc.
+// Malformed declarations are reported at the comment and skipped, so that a typo
+// does not declare a function pure.
+func (ea *EffectAnalysis) parseDocDecl(doc *ast.CommentGroup) (EffectExpr, bool) {
+	comment := declComment(doc)
+	if comment == nil {
+		return nil, false
+	}
+	expr, err := ParseEffectDecl(strings.TrimSpace(comment.Text))
+	if err != nil {
+		ea.Pass.Reportf(comment.Pos(), "invalid effect declaration: %v", err)
+		return nil, false
+	}
+	return expr, true
+}
+
+// declComment returns the first // dirty: comment in doc
+func declComment(doc *ast.CommentGroup) *ast.Comment {
+	if doc == nil {
+		return nil
+	}
+	for _, comment := range doc.List {
+		if strings.HasPrefix(strings.TrimSpace(comment.Text), "// dirty:") {
+			return comment
+		}
+	}
+	return nil
+}
+
+// lookupFunction returns the function info for fn, declared locally or in an imported package
+func (ea *EffectAnalysis) lookupFunction(fn *types.Func) (*FunctionInfo, bool) {
+	if info, ok := ea.Functions[FuncKey(fn)]; ok {
+		return info, true
+	}
+	if fn.Pkg() != nil && fn.Pkg() != ea.Pass.Pkg {
+		return ea.importedFunction(fn)
+	}
+	return nil, false
+}
+
+// importedFunction returns a synthetic function info for a function of another package
+// whose effects are known from Facts or JSON declarations
+func (ea *EffectAnalysis) importedFunction(fn *types.Func) (*FunctionInfo, bool) {
+	return ea.importedDecl(FuncKey(fn), fn.Pkg().Path(), fn.Type().(*types.Signature))
+}
+
+// importedDecl returns a synthetic function info for a declaration of another package.
+// Effect variables of JSON declarations are bound to the parameters of sig, if any.
+func (ea *EffectAnalysis) importedDecl(funcName, pkgPath string, sig *types.Signature) (*FunctionInfo, bool) {
+	if info, ok := ea.Functions[funcName]; ok {
+		return info, true
+	}
+
+	ea.importTransitiveEffects(pkgPath)
+	effects, source := ea.Resolver.ResolveEffects(funcName)
+	params := ea.Resolver.ResolveEffectParams(funcName)
+	var decl EffectExpr
+	if source == SourceUnknown || source == SourceJSON {
+		// JSON declarations may use effect variables, so bind them to the signature
+		expr, ok := ea.Resolver.ResolveJSONExpr(funcName, pkgPath)
+		if !ok {
+			return nil, false
+		}
+		if sig != nil {
+			expr, params = bindSignatureParams(sig, expr)
+		}
+		expr = ea.Definitions.Bind(expr)
+		var err error
+		if effects, err = ea.evalDecl(expr, token.NoPos); err != nil {
+			return nil, false
+		}
+		decl = expr
+	}
+
+	info := &FunctionInfo{
+		Name:            funcName,
+		Package:         pkgPath,
+		DeclaredEffects: effects,
+		ComputedEffects: effects,
+		HasDeclaration:  true, // Treat as declared since it's from Facts/JSON
+		EffectParams:    params,
+		CallSites:       []CallSite{},
+		AsyncEffects:    ea.Resolver.ResolveAsyncEffects(funcName),
+	}
+	ea.applyAsyncDeclaration(info, decl, token.NoPos)
+	ea.Functions[funcName] = info
+	return info, true
 }
 
 // BuildCallGraph analyzes function bodies to build the call graph
 func (ea *EffectAnalysis) BuildCallGraph() {
+	ea.collectTypeParamCalls()
+	ea.buildInitCalls()
+
 	for funcName, info := range ea.Functions {
 		// Analyze function body for calls
-		ast.Inspect(info.Decl, func(n ast.Node) bool {
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
+		ea.inspectCalls(info, func(call *ast.CallExpr, kind CallKind) {
+			// Resolve the called function through type information
+			callee := ea.calleeFunc(call)
 
-			// Extract called function name
-			var calleeName string
-			switch fun := call.Fun.(type) {
-			case *ast.Ident:
-				calleeName = fun.Name
-			case *ast.SelectorExpr:
-				// Handle method calls
-				calleeName = fun.Sel.Name
+			// Dynamic calls go to the targets found by the SSA backend, if selected
+			if targets, ok := ea.dynamicTargets(info, call); ok {
+				debugLog("Dynamic call at %v: syntax=%v %s=%v", ea.Pass.Fset.Position(call.Pos()),
+					ea.funcValues(call.Fun).ToSlice(), ea.CallGraphMode, targets.ToSlice())
+				for _, calleeName := range targets.ToSlice() {
+					ea.addCallSite(info, CallSite{Callee: calleeName, Position: call.Pos(), Kind: kind})
+				}
+				var calleeInfo *FunctionInfo
+				if callee != nil {
+					calleeInfo, _ = ea.lookupFunction(callee)
+				}
+				ea.escapingFuncs(call, calleeInfo, func(arg ast.Expr, argName string) {
+					ea.addCallSite(info, CallSite{Callee: argName, Position: arg.Pos(), Kind: kind})
+				})
+				return
 			}
 
-			if calleeName != "" {
-				// Check if the called function is in our analysis
-				if _, exists := ea.Functions[calleeName]; exists {
-					info.CallSites = append(info.CallSites, CallSite{
-						Callee:   calleeName,
-						Position: call.Pos(),
-					})
-					ea.CallGraph.AddCall(funcName, calleeName, call.Pos())
+			if callee == nil {
+				// Calls of function values with a contract get the declared effects
+				if contract, ok := ea.funcContract(ea.assignedObject(call.Fun), ea.Pass.TypesInfo.TypeOf(call.Fun)); ok {
+					ea.addCallSite(info, CallSite{Callee: contract.Name, Position: call.Pos(), Kind: kind})
+					return
 				}
 
-				// Always check JSON effects, even if function exists
-				if ea.JSONEffects != nil {
-					if effectExpr, ok := ea.JSONEffects[calleeName]; ok {
-						// Evaluate the effect expression
-						effectSet, err := effectExpr.Eval(nil)
-						if err == nil {
-							// If function already exists, update its effects
-							if existingFunc, exists := ea.Functions[calleeName]; exists {
-								// Only update if it doesn't have a declaration
-								if !existingFunc.HasDeclaration {
-									existingFunc.DeclaredEffects = effectSet
-									existingFunc.ComputedEffects = effectSet
-									existingFunc.HasDeclaration = true // Treat JSON as declaration
-								}
-							} else {
-								// Create a synthetic function info for JSON function
-								jsonFunc := &FunctionInfo{
-									Name:            calleeName,
-									Package:         ea.Pass.Pkg.Path(),
-									DeclaredEffects: effectSet,
-									ComputedEffects: effectSet,
-									HasDeclaration:  true, // Treat as if it has declaration
-								}
-								ea.Functions[calleeName] = jsonFunc
-
-								// Add to call graph
-								info.CallSites = append(info.CallSites, CallSite{
-									Callee:   calleeName,
-									Position: call.Pos(),
-								})
-								ea.CallGraph.AddCall(funcName, calleeName, call.Pos())
-							}
-						}
-					}
+				// Other calls of function values go to the functions they may hold
+				for _, calleeName := range ea.funcValues(call.Fun).ToSlice() {
+					ea.addCallSite(info, CallSite{Callee: calleeName, Position: call.Pos(), Kind: kind})
+				}
+				ea.escapingFuncs(call, nil, func(arg ast.Expr, calleeName string) {
+					ea.addCallSite(info, CallSite{Callee: calleeName, Position: arg.Pos(), Kind: kind})
+				})
+				return
+			}
+			calleeName := FuncKey(callee)
+
+			// Check if the called function is in our analysis.
+			// Calls into other packages are resolved in the cross-package phase.
+			calleeInfo, exists := ea.Functions[calleeName]
+			if exists && callee.Pkg() == ea.Pass.Pkg {
+				_, viaTypeParam := ea.typeParamRecv(call)
+				site := CallSite{
+					Callee:          calleeName,
+					Position:        call.Pos(),
+					Kind:            kind,
+					Instantiations:  ea.instantiateEffectParams(funcName, calleeInfo, call),
+					TypeParamMethod: viaTypeParam,
+					Promoted:        ea.promotedThrough(call),
+				}
+				methods, forwarded, ok := ea.instantiateTypeParams(funcName, calleeInfo, call)
+				site.Instantiations = append(site.Instantiations, methods...)
+				site.Substituted = ok
+				ea.addCallSite(info, site)
+				// Constraint methods called through the callee count as calls on type parameters
+				for _, name := range forwarded {
+					ea.addCallSite(info, CallSite{Callee: name, Position: call.Pos(), Kind: kind, TypeParamMethod: true})
 				}
+			} else if inferred, ok := ea.inferEffects(call); ok {
+				// Library calls with effects inferred from their arguments
+				calleeInfo = ea.inferredCallee(callee)
+				ea.addCallSite(info, CallSite{Callee: calleeInfo.Name, Position: call.Pos(), Kind: kind, Inferred: inferred})
+			} else {
+				calleeInfo, _ = ea.lookupFunction(callee)
 			}
 
-			return true
+			// Functions passed as arguments may be called by the callee
+			ea.escapingFuncs(call, calleeInfo, func(arg ast.Expr, argName string) {
+				ea.addCallSite(info, CallSite{Callee: argName, Position: arg.Pos(), Kind: kind})
+			})
 		})
 	}
 }
 
+// addCallSite records a call from info in its call sites and in the call graph
+func (ea *EffectAnalysis) addCallSite(info *FunctionInfo, call CallSite) {
+	info.CallSites = append(info.CallSites, call)
+	ea.CallGraph.AddCallSite(info.Name, call)
+}
+
 // PropagateEffects computes implicit effects using a worklist algorithm
 func (ea *EffectAnalysis) PropagateEffects() {
 	// Initialize worklist with all functions
@@ -168,16 +377,25 @@ func (ea *EffectAnalysis) PropagateEffects() {
 
 		fn := ea.Functions[funcName]
 		oldEffects := fn.ComputedEffects.Clone()
+		if fn.AsyncEffects == nil {
+			fn.AsyncEffects = NewStringSet()
+		}
+		oldAsync := len(fn.AsyncEffects)
+		oldBase := len(fn.BaseEffects)
 
 		// Collect effects from all called functions
 		for _, call := range fn.CallSites {
-			if callee, ok := ea.Functions[call.Callee]; ok {
-				fn.ComputedEffects.AddAll(callee.ComputedEffects)
+			if effects, async, ok := ea.callEffects(fn, call); ok {
+				fn.ComputedEffects.AddAll(effects)
+				fn.AsyncEffects.AddAll(async)
+				if fn.BaseEffects != nil && !call.TypeParamMethod {
+					fn.BaseEffects.AddAll(effects)
+				}
 			}
 		}
 
 		// If effects changed, add callers to worklist
-		if !oldEffects.Equals(fn.ComputedEffects) {
+		if !oldEffects.Equals(fn.ComputedEffects) || len(fn.AsyncEffects) != oldAsync || len(fn.BaseEffects) != oldBase {
 			for _, caller := range ea.CallGraph.CalledBy[funcName] {
 				if !inWorklist[caller] {
 					worklist = append(worklist, caller)
@@ -198,25 +416,43 @@ func (ea *EffectAnalysis) CheckEffects() {
 
 		// Check each call site
 		for _, call := range fn.CallSites {
-			if callee, ok := ea.Functions[call.Callee]; ok {
+			if calleeEffects, async, ok := ea.callEffects(fn, call); ok {
+				callee := ea.Functions[call.Callee]
+				ea.checkAsyncEffects(fn, call, async)
+
 				// Check if called function's effects are declared
-				if !callee.ComputedEffects.IsSubsetOf(fn.DeclaredEffects) {
-					missingEffects := callee.ComputedEffects.Difference(fn.DeclaredEffects)
+				if !ea.Lattice.CoversAll(fn.DeclaredEffects, calleeEffects) {
+					missingEffects := ea.Lattice.Uncovered(fn.DeclaredEffects, calleeEffects)
 
 					// Build detailed error
 					err := &EffectError{
 						CallSite:       call.Position,
-						Caller:         fn.Name,
-						Callee:         call.Callee,
+						Caller:         ea.displayName(fn.Name),
+						Callee:         ea.displayName(call.Callee),
 						CallerEffects:  fn.DeclaredEffects.ToSlice(),
-						CalleeEffects:  callee.ComputedEffects.ToSlice(),
+						CalleeEffects:  calleeEffects.ToSlice(),
 						MissingEffects: missingEffects.ToSlice(),
+						Wildcards:      fn.DeclaredEffects.Wildcards().ToSlice(),
+						CoveredBy:      make(map[string]string),
+						Promoted:       call.Promoted,
+					}
+					for effect := range calleeEffects {
+						if label, ok := ea.Lattice.Covers(fn.DeclaredEffects, effect); ok && label != effect {
+							err.CoveredBy[effect] = label
+						}
 					}
 
 					// Add propagation path if callee has no declaration
 					if !callee.HasDeclaration {
 						visited := make(map[string]bool)
 						err.PropagationPath = BuildPropagationPath(call.Callee, ea.Functions, visited)
+						for i := range err.PropagationPath {
+							step := &err.PropagationPath[i]
+							step.Function = ea.displayName(step.Function)
+							if step.Source != "computed" {
+								step.Source = ea.displayName(step.Source)
+							}
+						}
 					}
 
 					// Check if verbose mode is enabled
@@ -226,11 +462,17 @@ func (ea *EffectAnalysis) CheckEffects() {
 							Pos:     call.Position,
 							Message: err.Format(),
 						})
+					} else if fn.Name == ea.initName() {
+						ea.Pass.Reportf(call.Position,
+							"package initialization calls %s which has effects [%s] not declared for this package%s",
+							ea.displayName(call.Callee), joinEffects(calleeEffects.ToSlice()),
+							wildcardNote(fn.DeclaredEffects, missingEffects))
 					} else {
 						// Use simple format
 						ea.Pass.Reportf(call.Position,
-							"function calls %s which has effects [%s] not declared in this function",
-							call.Callee, joinEffects(callee.ComputedEffects.ToSlice()))
+							"function calls %s which has effects [%s] not declared in this function%s",
+							ea.displayName(call.Callee), joinEffects(calleeEffects.ToSlice()),
+							wildcardNote(fn.DeclaredEffects, missingEffects))
 					}
 				}
 			}
@@ -238,6 +480,15 @@ func (ea *EffectAnalysis) CheckEffects() {
 	}
 }
 
+// effectsOf returns the effects of a function known to this analysis or to the resolver
+func (ea *EffectAnalysis) effectsOf(funcName string) (StringSet, bool) {
+	if info, ok := ea.Functions[funcName]; ok {
+		return info.ComputedEffects, true
+	}
+	effects, source := ea.Resolver.ResolveEffects(funcName)
+	return effects, source != SourceUnknown
+}
+
 // joinEffects joins effect strings for error messages
 func joinEffects(effects []string) string {
 	result := ""
@@ -18,7 +18,11 @@ func LoadEffectDeclarations(path string) (*EffectDeclarations, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseEffectDeclarations(data)
+}
 
+// parseEffectDeclarations parses effect declarations from JSON
+func parseEffectDeclarations(data []byte) (*EffectDeclarations, error) {
 	var decls EffectDeclarations
 	if err := json.Unmarshal(data, &decls); err != nil {
 		return nil, err
@@ -32,6 +36,15 @@ func LoadEffectDeclarations(path string) (*EffectDeclarations, error) {
 	return &decls, nil
 }
 
+// WriteEffectDeclarations writes effect declarations as JSON, with sorted keys
+func WriteEffectDeclarations(path string, decls *EffectDeclarations) error {
+	data, err := json.MarshalIndent(decls, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644) // #nosec G306 - registries are not secret
+}
+
 // ParsedEffects holds parsed effect expressions
 type ParsedEffects map[string]EffectExpr
 
@@ -23,6 +23,12 @@ type UnifiedEffectResolver struct {
 	// importedFacts contains effects imported from other packages via Facts
 	importedFacts map[string]StringSet
 
+	// importedParams contains effect parameters of polymorphic functions imported via Facts
+	importedParams map[string][]int
+
+	// importedAsync contains the effects of goroutines spawned by functions imported via Facts
+	importedAsync map[string]StringSet
+
 	// jsonEffects contains effects declared in JSON files
 	jsonEffects ParsedEffects
 }
@@ -30,9 +36,11 @@ type UnifiedEffectResolver struct {
 // NewUnifiedEffectResolver creates a new unified effect resolver
 func NewUnifiedEffectResolver() *UnifiedEffectResolver {
 	return &UnifiedEffectResolver{
-		localEffects:  make(map[string]*FunctionInfo),
-		importedFacts: make(map[string]StringSet),
-		jsonEffects:   make(ParsedEffects),
+		localEffects:   make(map[string]*FunctionInfo),
+		importedFacts:  make(map[string]StringSet),
+		importedParams: make(map[string][]int),
+		importedAsync:  make(map[string]StringSet),
+		jsonEffects:    make(ParsedEffects),
 	}
 }
 
@@ -61,6 +69,41 @@ func (r *UnifiedEffectResolver) ResolveEffects(funcName string) (StringSet, Effe
 	return nil, SourceUnknown
 }
 
+// ResolveJSONEffects looks up the JSON declaration for a function by its qualified name,
+// then by its name relative to pkgPath (e.g. "GetUser" or "(*Repo).Find")
+func (r *UnifiedEffectResolver) ResolveJSONEffects(funcName, pkgPath string) (StringSet, bool) {
+	effectExpr, ok := r.ResolveJSONExpr(funcName, pkgPath)
+	if !ok {
+		return nil, false
+	}
+	effects, err := effectExpr.Eval(nil)
+	if err != nil {
+		return nil, false
+	}
+	return effects, true
+}
+
+// ResolveJSONExpr returns the unevaluated JSON declaration for a function,
+// looked up the same way as ResolveJSONEffects
+func (r *UnifiedEffectResolver) ResolveJSONExpr(funcName, pkgPath string) (EffectExpr, bool) {
+	for _, name := range []string{funcName, RelativeName(funcName, pkgPath)} {
+		if effectExpr, ok := r.jsonEffects[name]; ok {
+			return effectExpr, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveEffectParams returns the effect parameters of a polymorphic function imported from Facts
+func (r *UnifiedEffectResolver) ResolveEffectParams(funcName string) []int {
+	return r.importedParams[funcName]
+}
+
+// ResolveAsyncEffects returns the async effects of a function imported from Facts
+func (r *UnifiedEffectResolver) ResolveAsyncEffects(funcName string) StringSet {
+	return r.importedAsync[funcName]
+}
+
 // AddLocalFunction adds a function from the current package
 func (r *UnifiedEffectResolver) AddLocalFunction(funcName string, info *FunctionInfo) {
 	r.localEffects[funcName] = info
@@ -71,6 +114,16 @@ func (r *UnifiedEffectResolver) AddImportedEffects(funcName string, effects Stri
 	r.importedFacts[funcName] = effects
 }
 
+// AddImportedEffectParams adds effect parameters of a polymorphic function imported from Facts
+func (r *UnifiedEffectResolver) AddImportedEffectParams(funcName string, params []int) {
+	r.importedParams[funcName] = params
+}
+
+// AddImportedAsyncEffects adds the async effects of a function imported from Facts
+func (r *UnifiedEffectResolver) AddImportedAsyncEffects(funcName string, effects StringSet) {
+	r.importedAsync[funcName] = effects
+}
+
 // SetJSONEffects sets the JSON effect declarations
 func (r *UnifiedEffectResolver) SetJSONEffects(effects ParsedEffects) {
 	r.jsonEffects = effects
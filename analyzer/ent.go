@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+)
+
+// entPackage is the import path of the ent runtime imported by generated ent packages
+const entPackage = "entgo.io/ent"
+
+// entBuilders maps the suffixes of ent's generated builder and client types to the
+// operations their executing methods run, longest suffixes first
+var entBuilders = []struct {
+	suffix string
+	ops    []string
+}{
+	{"CreateBulk", []string{"insert"}},
+	{"UpsertBulk", []string{"insert", "update"}},
+	{"UpsertOne", []string{"insert", "update"}},
+	{"UpdateOne", []string{"update"}},
+	{"DeleteOne", []string{"delete"}},
+	{"GroupBy", []string{"select"}},
+	{"Create", []string{"insert"}},
+	{"Upsert", []string{"insert", "update"}},
+	{"Update", []string{"update"}},
+	{"Delete", []string{"delete"}},
+	{"Select", []string{"select"}},
+	{"Query", []string{"select"}},
+	{"Client", []string{"select"}},
+}
+
+// entInferer infers database effects from the builders generated by ent:
+// the operation from the builder type, such as UserCreate or UserQuery, and
+// the table from the Table constant of the entity's generated package
+type entInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (entInferer) Name() string { return "ent" }
+
+// Infer returns the effects of a call of a method of a generated ent builder or
+// client that runs a statement: those taking a context, such as Save, Exec and All
+func (entInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	sig := callee.Type().(*types.Signature)
+	if sig.Recv() == nil || sig.Params().Len() == 0 || !isContext(sig.Params().At(0).Type()) {
+		return nil, false
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok || !importsPackage(callee.Pkg(), entPackage) {
+		return nil, false
+	}
+
+	for _, builder := range entBuilders {
+		entity, ok := strings.CutSuffix(named.Obj().Name(), builder.suffix)
+		if !ok || entity == "" {
+			continue
+		}
+		table, ok := entTable(callee.Pkg(), entity)
+		if !ok {
+			return nil, false
+		}
+		effects := NewStringSet()
+		for _, op := range builder.ops {
+			effects.Add(op + "[" + table + "]")
+		}
+		return effects, true
+	}
+	return nil, false
+}
+
+// entTable returns the table of an entity from the Table constant of the package ent
+// generates for it next to the client, "ent/user" for the User entity. It holds the
+// table name of the entity's schema.
+func entTable(pkg *types.Package, entity string) (string, bool) {
+	path := pkg.Path() + "/" + strings.ToLower(entity)
+	for _, imp := range pkg.Imports() {
+		if imp.Path() != path {
+			continue
+		}
+		table, ok := imp.Scope().Lookup("Table").(*types.Const)
+		if !ok || table.Val().Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(table.Val()), true
+	}
+	return "", false
+}
+
+// isContext reports whether t is context.Context
+func isContext(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// importsPackage reports whether pkg imports the package at path
+func importsPackage(pkg *types.Package, path string) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == path {
+			return true
+		}
+	}
+	return false
+}
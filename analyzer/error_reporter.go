@@ -14,7 +14,10 @@ type EffectError struct {
 	CallerEffects   []string
 	CalleeEffects   []string
 	MissingEffects  []string
+	Wildcards       []string          // 呼び出し元の宣言のワイルドカード
+	CoveredBy       map[string]string // ワイルドカードや階層で満たされたエフェクトと、それを満たした宣言のラベル
 	PropagationPath []PropagationStep
+	Promoted        []string // 埋め込みフィールドを通して昇格したメソッドの場合、そのフィールド
 }
 
 // PropagationStep represents one step in the effect propagation chain
@@ -34,6 +37,10 @@ func (e *EffectError) Format() string {
 
 	// 詳細情報
 	b.WriteString("\n")
+	if len(e.Promoted) > 0 {
+		b.WriteString(fmt.Sprintf("  Called method '%s' is promoted through embedded field '%s'\n",
+			e.Callee, strings.Join(e.Promoted, ".")))
+	}
 	b.WriteString(fmt.Sprintf("  Called function '%s' requires:\n", e.Callee))
 	for _, effect := range e.CalleeEffects {
 		b.WriteString(fmt.Sprintf("    - %s\n", effect))
@@ -55,6 +62,22 @@ func (e *EffectError) Format() string {
 		b.WriteString(fmt.Sprintf("    - %s\n", effect))
 	}
 
+	// ワイルドカードや階層で満たされたエフェクトと、ワイルドカードが満たさなかったエフェクト
+	if len(e.CoveredBy) > 0 || len(e.Wildcards) > 0 {
+		b.WriteString("\n")
+		b.WriteString("  Coverage:\n")
+		for _, effect := range e.CalleeEffects {
+			if label, ok := e.CoveredBy[effect]; ok {
+				b.WriteString(fmt.Sprintf("    - %s: covered by %s\n", effect, label))
+			}
+		}
+		if len(e.Wildcards) > 0 {
+			for _, effect := range e.MissingEffects {
+				b.WriteString(fmt.Sprintf("    - %s: not covered by %s\n", effect, strings.Join(e.Wildcards, ", ")))
+			}
+		}
+	}
+
 	// エフェクト伝播経路（暗黙的エフェクトの場合）
 	if len(e.PropagationPath) > 0 {
 		b.WriteString("\n")
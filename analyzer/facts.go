@@ -12,6 +12,18 @@ type PackageEffectsFact struct {
 	// Map from function name to its effects
 	// Key format: "FunctionName" for functions, "(*Type).Method" for methods
 	FunctionEffects map[string][]string
+
+	// Map from polymorphic function name to the indices of its function-typed
+	// parameters bound to effect variables
+	EffectParams map[string][]int
+
+	// Map from function name to the effects of the goroutines it spawns,
+	// for functions keeping them apart with an async clause
+	AsyncEffects map[string][]string
+
+	// Effects of the package initialization: package-level variable initializers,
+	// init functions and the initialization of imported packages
+	InitEffects []string
 }
 
 // AFact marks PackageEffectsFact as a fact type for the analysis framework
@@ -26,6 +38,12 @@ func (f *PackageEffectsFact) String() string {
 // This is attached to individual function objects.
 type FunctionEffectsFact struct {
 	Effects []string
+	// Declared means the effects come from a declaration rather than from the
+	// function's body, which may call functions with unknown effects
+	Declared bool
+	// Known means the effects of a function without a declaration are fully known
+	// in strict mode: no call in its body, nor in the bodies of its callees, is unknown
+	Known bool
 }
 
 // AFact marks FunctionEffectsFact as a fact type
@@ -40,4 +58,6 @@ func (f *FunctionEffectsFact) String() string {
 func init() {
 	gob.Register(&PackageEffectsFact{})
 	gob.Register(&FunctionEffectsFact{})
+	gob.Register(&TableNameFact{})
+	gob.Register(&EffectDefinitionsFact{})
 }
@@ -11,34 +11,60 @@ func (ea *EffectAnalysis) ExportPackageEffects() {
 	// Create package fact with all function effects
 	packageFact := &PackageEffectsFact{
 		FunctionEffects: make(map[string][]string),
+		EffectParams:    make(map[string][]int),
+		AsyncEffects:    make(map[string][]string),
+	}
+
+	// Functions whose effects are only known in part, in strict mode
+	var unknown StringSet
+	if ea.StrictMode != StrictOff {
+		unknown = ea.partlyUnknown()
 	}
 
 	// Collect effects for all functions in the package
 	for funcName, info := range ea.Functions {
+		// Skip synthetic entries for functions of other packages and function literals
+		if info.Package != ea.Pass.Pkg.Path() || (info.Object == nil && !info.Abstract) {
+			continue
+		}
 		if info.ComputedEffects == nil {
 			continue
 		}
 
-		// Store effects as sorted slice
+		// Store effects as sorted slice, keyed by the package-relative name
+		name := RelativeName(funcName, info.Package)
 		effects := info.ComputedEffects.ToSlice()
-		if len(effects) > 0 {
-			packageFact.FunctionEffects[funcName] = effects
+		// Empty contracts still constrain the implementations of other packages
+		if len(effects) > 0 || info.Abstract {
+			packageFact.FunctionEffects[name] = effects
+		}
+		if len(info.EffectParams) > 0 {
+			packageFact.EffectParams[name] = info.EffectParams
+		}
+		if len(info.AsyncEffects) > 0 {
+			packageFact.AsyncEffects[name] = info.AsyncEffects.ToSlice()
 		}
 
-		// Also export individual function facts for direct object queries
-		if info.Decl != nil && info.Decl.Name != nil {
-			funcObj := ea.Pass.TypesInfo.Defs[info.Decl.Name]
-			if funcObj != nil {
-				funcFact := &FunctionEffectsFact{
-					Effects: effects,
-				}
-				ea.Pass.ExportObjectFact(funcObj, funcFact)
-			}
+		// Also export individual function facts for direct object queries.
+		// Contracts of function types and fields have no function object.
+		if info.Object == nil {
+			continue
+		}
+		funcFact := &FunctionEffectsFact{
+			Effects:  effects,
+			Declared: info.HasDeclaration,
+			Known:    unknown != nil && !unknown.Contains(funcName),
 		}
+		ea.Pass.ExportObjectFact(info.Object, funcFact)
+	}
+
+	if info, ok := ea.Functions[ea.initName()]; ok {
+		packageFact.InitEffects = info.ComputedEffects.ToSlice()
 	}
 
 	// Export the package fact
-	if len(packageFact.FunctionEffects) > 0 {
+	if len(packageFact.FunctionEffects) > 0 || len(packageFact.EffectParams) > 0 ||
+		len(packageFact.AsyncEffects) > 0 || len(packageFact.InitEffects) > 0 {
 		ea.Pass.ExportPackageFact(packageFact)
 	}
 }
@@ -13,7 +13,45 @@ func (ea *EffectAnalysis) ImportAllPackageEffects() {
 
 	// Import effects from all imported packages
 	for _, pkg := range ea.Pass.Pkg.Imports() {
-		ea.ImportPackageEffectsIntoResolver(pkg)
+		ea.importPackageEffects(pkg)
+	}
+}
+
+// importPackageEffects imports the effects of pkg into the resolver once
+func (ea *EffectAnalysis) importPackageEffects(pkg *types.Package) {
+	if ea.importedPkgs[pkg.Path()] {
+		return
+	}
+	ea.importedPkgs[pkg.Path()] = true
+	ea.ImportPackageEffectsIntoResolver(pkg)
+}
+
+// importTransitiveEffects imports the effects of the package at path on demand, when
+// it is only imported indirectly: its functions are reached through promoted methods,
+// fields and contracts of the types of the packages imported directly
+func (ea *EffectAnalysis) importTransitiveEffects(path string) {
+	if ea.DisableFacts || ea.importedPkgs[path] {
+		return
+	}
+	seen := make(map[*types.Package]bool)
+	var find func(pkgs []*types.Package) *types.Package
+	find = func(pkgs []*types.Package) *types.Package {
+		for _, pkg := range pkgs {
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+			if pkg.Path() == path {
+				return pkg
+			}
+			if found := find(pkg.Imports()); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	if pkg := find(ea.Pass.Pkg.Imports()); pkg != nil {
+		ea.importPackageEffects(pkg)
 	}
 }
 
@@ -30,4 +68,23 @@ func (ea *EffectAnalysis) ImportPackageEffectsIntoResolver(pkg *types.Package) {
 		qualifiedName := pkg.Path() + "." + funcName
 		ea.Resolver.AddImportedEffects(qualifiedName, NewStringSetFromSlice(effects))
 	}
+	for funcName, params := range packageFact.EffectParams {
+		qualifiedName := pkg.Path() + "." + funcName
+		ea.Resolver.AddImportedEffectParams(qualifiedName, params)
+		// Polymorphic functions may have no effects of their own
+		if _, ok := packageFact.FunctionEffects[funcName]; !ok {
+			ea.Resolver.AddImportedEffects(qualifiedName, NewStringSet())
+		}
+	}
+	if len(packageFact.InitEffects) > 0 {
+		ea.Resolver.AddImportedEffects(pkg.Path()+".init", NewStringSetFromSlice(packageFact.InitEffects))
+	}
+	for funcName, effects := range packageFact.AsyncEffects {
+		qualifiedName := pkg.Path() + "." + funcName
+		ea.Resolver.AddImportedAsyncEffects(qualifiedName, NewStringSetFromSlice(effects))
+		// Functions may have async effects only
+		if _, ok := packageFact.FunctionEffects[funcName]; !ok {
+			ea.Resolver.AddImportedEffects(qualifiedName, NewStringSet())
+		}
+	}
 }
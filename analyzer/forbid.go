@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// ForbiddenEffects are the effects a //dirty-forbid: comment forbids to a function,
+// to the methods of a type or to every function of the package
+type ForbiddenEffects struct {
+	Effects StringSet
+	// Scope describes what the comment is on for diagnostics: "in this function",
+	// "for type User" or "in this package"
+	Scope string
+}
+
+// CollectForbidden collects the //dirty-forbid: comments in the docs of functions,
+// types and the package, and records the effects they forbid to each function unit
+func (ea *EffectAnalysis) CollectForbidden() {
+	var pkgForbidden []*ForbiddenEffects
+	for _, file := range ea.Pass.Files {
+		if f := ea.parseForbidden(file.Doc, "in this package"); f != nil {
+			pkgForbidden = append(pkgForbidden, f)
+		}
+	}
+
+	typeForbidden := make(map[*types.TypeName]*ForbiddenEffects)
+	ea.Inspector.Preorder([]ast.Node{(*ast.GenDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.GenDecl)
+		if decl.Tok != token.TYPE {
+			return
+		}
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.TypeSpec)
+			// The doc of an unparenthesized declaration is attached to the GenDecl
+			doc := spec.Doc
+			if doc == nil && !decl.Lparen.IsValid() {
+				doc = decl.Doc
+			}
+			obj, ok := ea.Pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+			if !ok {
+				continue
+			}
+			if f := ea.parseForbidden(doc, "for type "+spec.Name.Name); f != nil {
+				typeForbidden[obj] = f
+			}
+		}
+	})
+
+	for _, fn := range ea.Functions {
+		if fn.Package != ea.Pass.Pkg.Path() || fn.Node() == nil && fn.Name != ea.initName() {
+			continue
+		}
+		var forbidden []*ForbiddenEffects
+		if fn.Decl != nil {
+			if f := ea.parseForbidden(fn.Decl.Doc, "in this function"); f != nil {
+				forbidden = append(forbidden, f)
+			}
+			if f, ok := typeForbidden[receiverTypeName(fn.Object)]; ok {
+				forbidden = append(forbidden, f)
+			}
+		}
+		fn.Forbidden = append(forbidden, pkgForbidden...)
+	}
+}
+
+// parseForbidden returns the effects forbidden by the //dirty-forbid: comment in doc,
+// reporting invalid ones
+func (ea *EffectAnalysis) parseForbidden(doc *ast.CommentGroup, scope string) *ForbiddenEffects {
+	if doc == nil {
+		return nil
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		if !strings.HasPrefix(text, "//dirty-forbid:") && !strings.HasPrefix(text, "// dirty-forbid:") {
+			continue
+		}
+		expr, err := ParseForbiddenEffects(text)
+		if err != nil {
+			ea.Pass.Reportf(comment.Pos(), "invalid forbidden effects: %v", err)
+			return nil
+		}
+		effects, err := ea.evalDecl(ea.Definitions.Bind(expr), comment.Pos())
+		if err != nil {
+			return nil
+		}
+		return &ForbiddenEffects{Effects: effects, Scope: scope}
+	}
+	return nil
+}
+
+// receiverTypeName returns the named type of the receiver of a method, nil for functions
+func receiverTypeName(fn *types.Func) *types.TypeName {
+	if fn == nil {
+		return nil
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Origin().Obj()
+	}
+	return nil
+}
+
+// CheckForbidden reports the calls bringing forbidden effects into function units,
+// whether they have a declaration or not. A call of a unit with the same forbidden
+// effects is reported in that unit instead, where the effects come in.
+func (ea *EffectAnalysis) CheckForbidden() {
+	for _, fn := range ea.Functions {
+		for _, forbidden := range fn.Forbidden {
+			if fn.HasDeclaration {
+				if effects := ea.Lattice.Overlapping(forbidden.Effects, fn.DeclaredEffects); len(effects) > 0 {
+					if pos := ea.declPos(fn); pos.IsValid() {
+						ea.Pass.Reportf(pos, "function declares effects [%s] forbidden %s",
+							joinEffects(effects.ToSlice()), forbidden.Scope)
+					}
+				}
+			}
+
+			for _, call := range fn.CallSites {
+				if callee, ok := ea.Functions[call.Callee]; ok && callee.forbids(forbidden) {
+					continue
+				}
+				sync, async, ok := ea.callEffects(fn, call)
+				if !ok {
+					continue
+				}
+				effects := ea.Lattice.Overlapping(forbidden.Effects, sync.Union(async))
+				if len(effects) == 0 {
+					continue
+				}
+				caller := "function"
+				if fn.Name == ea.initName() {
+					caller = "package initialization"
+				}
+				ea.Pass.Reportf(call.Position, "%s calls %s which has effects [%s] forbidden %s",
+					caller, ea.displayName(call.Callee), joinEffects(effects.ToSlice()), forbidden.Scope)
+			}
+		}
+	}
+}
+
+// declPos returns the position of the // dirty: comment of a function, or of the
+// function for JSON declarations
+func (ea *EffectAnalysis) declPos(fn *FunctionInfo) token.Pos {
+	if fn.Decl != nil {
+		if comment := declComment(fn.Decl.Doc); comment != nil {
+			return comment.Pos()
+		}
+	}
+	if node := fn.Node(); node != nil {
+		return node.Pos()
+	}
+	return token.NoPos
+}
+
+// forbids reports whether the same comment forbids effects to f
+func (f *FunctionInfo) forbids(forbidden *ForbiddenEffects) bool {
+	for _, other := range f.Forbidden {
+		if other == forbidden {
+			return true
+		}
+	}
+	return false
+}
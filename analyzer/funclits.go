@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// collectFuncLits registers every function literal as an analysis unit of its own.
+// Literals are named after their enclosing function, like "pkg.Outer$1" and
+// "pkg.Outer$1$1"; literals in package-level declarations belong to "pkg.init".
+func (ea *EffectAnalysis) collectFuncLits() {
+	counts := make(map[string]int)
+
+	// Collect from a snapshot, since registering literals grows ea.Functions
+	var decls []*FunctionInfo
+	for _, info := range ea.Functions {
+		if info.Decl != nil && info.Decl.Body != nil {
+			decls = append(decls, info)
+		}
+	}
+	for _, info := range decls {
+		ea.collectFuncLitsIn(info.Name, info.Decl.Body, counts)
+	}
+
+	initName := ea.initName()
+	for _, file := range ea.Pass.Files {
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok {
+				ea.collectFuncLitsIn(initName, gen, counts)
+			}
+		}
+	}
+}
+
+// collectFuncLitsIn registers the function literals directly nested in root
+func (ea *EffectAnalysis) collectFuncLitsIn(parent string, root ast.Node, counts map[string]int) {
+	ast.Inspect(root, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		counts[parent]++
+		info := &FunctionInfo{
+			Name:            fmt.Sprintf("%s$%d", parent, counts[parent]),
+			Package:         ea.Pass.Pkg.Path(),
+			DeclaredEffects: NewStringSet(),
+			ComputedEffects: NewStringSet(),
+			Lit:             lit,
+			CallSites:       []CallSite{},
+		}
+		if doc := ea.funcLitDoc(lit); doc != nil {
+			ea.applyDeclaration(info, doc)
+		}
+
+		ea.Functions[info.Name] = info
+		ea.funcLits[lit] = info.Name
+
+		// Literals nested in this one belong to it
+		ea.collectFuncLitsIn(info.Name, lit.Body, counts)
+		return false
+	})
+}
+
+// funcLitDoc returns the comment group annotating a function literal: a comment
+// ending on the line above the literal or earlier on the same line.
+// Doc comments of function declarations are never taken.
+func (ea *EffectAnalysis) funcLitDoc(lit *ast.FuncLit) *ast.CommentGroup {
+	tokFile := ea.Pass.Fset.File(lit.Pos())
+	if tokFile == nil {
+		return nil
+	}
+	line := tokFile.Line(lit.Pos())
+
+	for _, file := range ea.Pass.Files {
+		if file.FileStart > lit.Pos() || lit.Pos() > file.FileEnd {
+			continue
+		}
+
+		var doc *ast.CommentGroup
+		for _, group := range file.Comments {
+			if group.End() >= lit.Pos() {
+				break
+			}
+			if end := tokFile.Line(group.End()); end == line || end == line-1 {
+				doc = group
+			}
+		}
+		if doc == nil || ea.isFuncDeclDoc(file, doc) {
+			return nil
+		}
+		return doc
+	}
+	return nil
+}
+
+// isFuncDeclDoc reports whether group is the doc comment of a function declaration
+func (ea *EffectAnalysis) isFuncDeclDoc(file *ast.File, group *ast.CommentGroup) bool {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc == group {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectCalls calls fn for every call in the body of a function unit, along with
+// whether it is a plain call or the call of a go or defer statement.
+// Nested function literals are units of their own and are not visited.
+func (ea *EffectAnalysis) inspectCalls(info *FunctionInfo, fn func(call *ast.CallExpr, kind CallKind)) {
+	kinds := make(map[*ast.CallExpr]CallKind)
+	for _, root := range unitNodes(info) {
+		ast.Inspect(root, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return n == info.Lit
+			case *ast.GoStmt:
+				kinds[n.Call] = CallGo
+			case *ast.DeferStmt:
+				kinds[n.Call] = CallDefer
+			case *ast.CallExpr:
+				fn(n, kinds[n])
+			}
+			return true
+		})
+	}
+}
+
+// inspectReturns calls fn for every return statement of a function unit
+func (ea *EffectAnalysis) inspectReturns(info *FunctionInfo, fn func(ret *ast.ReturnStmt)) {
+	root := info.Node()
+	if root == nil {
+		return
+	}
+	ast.Inspect(root, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n == info.Lit
+		case *ast.ReturnStmt:
+			fn(n)
+		}
+		return true
+	})
+}
+
+// unitNodes returns the syntax analyzed for a function unit: its declaration, its
+// function literal or the package-level variable declarations of the package initialization
+func unitNodes(info *FunctionInfo) []ast.Node {
+	if root := info.Node(); root != nil {
+		return []ast.Node{root}
+	}
+	nodes := make([]ast.Node, len(info.Inits))
+	for i, decl := range info.Inits {
+		nodes[i] = decl
+	}
+	return nodes
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// collectFuncTypes registers the effect contracts of a named function type and of
+// the function-typed fields of a struct type. Calls of values of such types get the
+// declared effects, and functions assigned to them must respect the declaration.
+// Contracts are named like types and methods: "pkg.Handler" and "pkg.Server.OnStart".
+func (ea *EffectAnalysis) collectFuncTypes(spec *ast.TypeSpec, doc *ast.CommentGroup) {
+	obj, ok := ea.Pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok {
+		return
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.FuncType:
+		ea.addFuncContract(typeKey(obj), doc)
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			// The doc comment above a field or the comment after it
+			fieldDoc := field.Doc
+			if fieldDoc == nil {
+				fieldDoc = field.Comment
+			}
+			for _, name := range field.Names {
+				v, ok := ea.Pass.TypesInfo.Defs[name].(*types.Var)
+				if !ok || !isFuncType(v.Type()) {
+					continue
+				}
+				ea.addFuncContract(typeKey(obj)+"."+v.Name(), fieldDoc)
+			}
+		}
+	}
+}
+
+// addFuncContract registers a contract for function values if it is declared
+func (ea *EffectAnalysis) addFuncContract(name string, doc *ast.CommentGroup) {
+	info := &FunctionInfo{
+		Name:            name,
+		Package:         ea.Pass.Pkg.Path(),
+		DeclaredEffects: NewStringSet(),
+		ComputedEffects: NewStringSet(),
+		Abstract:        true,
+		CallSites:       []CallSite{},
+	}
+	ea.applyDeclaration(info, doc)
+
+	// Function values without a contract are tracked through their values
+	if !info.HasDeclaration {
+		return
+	}
+
+	ea.Functions[info.Name] = info
+	ea.Resolver.AddLocalFunction(info.Name, info)
+}
+
+// typeKey returns the qualified name of a package-level type
+func typeKey(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// funcContract returns the contract of a function value stored in obj or of type typ.
+// A contract on a struct field takes priority over one on the field's type.
+func (ea *EffectAnalysis) funcContract(obj types.Object, typ types.Type) (*FunctionInfo, bool) {
+	if v, ok := obj.(*types.Var); ok && v.IsField() {
+		if key, ok := ea.fieldKey(v); ok {
+			if info, ok := ea.contract(key, v.Pkg()); ok {
+				return info, true
+			}
+		}
+	}
+
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || !isFuncType(named) || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	named = named.Origin()
+	return ea.contract(typeKey(named.Obj()), named.Obj().Pkg())
+}
+
+// contract returns the contract called name, declared locally or in an imported package
+func (ea *EffectAnalysis) contract(name string, pkg *types.Package) (*FunctionInfo, bool) {
+	if info, ok := ea.Functions[name]; ok {
+		return info, info.Abstract
+	}
+	if pkg == nil || pkg == ea.Pass.Pkg {
+		return nil, false
+	}
+
+	info, ok := ea.importedDecl(name, pkg.Path(), nil)
+	if !ok {
+		return nil, false
+	}
+	info.Abstract = true
+	return info, true
+}
+
+// fieldKey returns the contract name of a struct field, "pkg.T.field".
+// Only fields of package-level struct types have contracts.
+func (ea *EffectAnalysis) fieldKey(field *types.Var) (string, bool) {
+	field = field.Origin()
+	if key, ok := ea.fieldKeys[field]; ok {
+		return key, key != ""
+	}
+
+	key := ""
+	if field.Pkg() != nil {
+		scope := field.Pkg().Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			st, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				if st.Field(i) == field {
+					key = typeKey(obj) + "." + field.Name()
+				}
+			}
+		}
+	}
+	ea.fieldKeys[field] = key
+	return key, key != ""
+}
+
+// CheckFuncContracts verifies that functions stored in variables, fields and
+// parameters of function types with a contract have no effects beyond it
+func (ea *EffectAnalysis) CheckFuncContracts() {
+	ea.forEachAssignment(func(a assignment) {
+		contract, ok := ea.funcContract(a.Obj, a.Dst)
+		if !ok {
+			return
+		}
+		// Values of the contract's own type have been checked where they got it
+		if src, ok := ea.funcContract(nil, ea.Pass.TypesInfo.TypeOf(a.Src)); ok && src == contract {
+			return
+		}
+
+		for _, funcName := range ea.funcValues(a.Src).ToSlice() {
+			effects, ok := ea.effectsOf(funcName)
+			if !ok || ea.Lattice.CoversAll(contract.DeclaredEffects, effects) {
+				continue
+			}
+			missing := ea.Lattice.Uncovered(contract.DeclaredEffects, effects)
+			ea.Pass.Reportf(a.Src.Pos(),
+				"function %s has effects [%s] not declared in the contract of %s%s",
+				ea.displayName(funcName), joinEffects(missing.ToSlice()),
+				ea.displayName(contract.Name), wildcardNote(contract.DeclaredEffects, missing))
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+// funcValueFlow is a value flowing into a variable, struct field or function result
+type funcValueFlow struct {
+	Obj    types.Object // destination variable or struct field
+	Result string       // or the function whose result it is
+	Src    ast.Expr
+}
+
+// BuildFuncValueFlow computes which functions may be held by variables, parameters,
+// struct fields and function results, so that calls of function values can be
+// attributed to the functions actually called. The analysis is flow-insensitive:
+// every assignment and call anywhere in the package is considered, until no set changes.
+func (ea *EffectAnalysis) BuildFuncValueFlow() {
+	// Effect parameters stand for the functions passed at each call site instead
+	effectParams := make(map[types.Object]bool)
+	for _, info := range ea.Functions {
+		if info.Object == nil {
+			continue
+		}
+		params := info.Object.Type().(*types.Signature).Params()
+		for _, i := range info.EffectParams {
+			effectParams[params.At(i)] = true
+		}
+	}
+
+	var flows []funcValueFlow
+	ea.forEachAssignment(func(a assignment) {
+		if a.Obj != nil && isFuncType(a.Obj.Type()) && !effectParams[varOrigin(a.Obj)] {
+			flows = append(flows, funcValueFlow{Obj: a.Obj, Src: a.Src})
+		}
+	})
+	for _, info := range ea.Functions {
+		ea.inspectReturns(info, func(ret *ast.ReturnStmt) {
+			for _, result := range ret.Results {
+				flows = append(flows, funcValueFlow{Result: info.Name, Src: result})
+			}
+		})
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, flow := range flows {
+			values := ea.funcValues(flow.Src)
+			if flow.Obj != nil {
+				changed = addValues(ea.FuncVars, varOrigin(flow.Obj), values) || changed
+			} else {
+				changed = addValues(ea.FuncResults, flow.Result, values) || changed
+			}
+		}
+	}
+}
+
+// addValues adds values to the set stored under key and reports whether it grew
+func addValues[K comparable](m map[K]StringSet, key K, values StringSet) bool {
+	if len(values) == 0 {
+		return false
+	}
+	set, ok := m[key]
+	if !ok {
+		set = NewStringSet()
+		m[key] = set
+	}
+	before := len(set)
+	set.AddAll(values)
+	return len(set) != before
+}
+
+// funcValues returns the names of the functions an expression may evaluate to:
+// function literals, references to functions, method values and method expressions,
+// and the functions held by variables
+func (ea *EffectAnalysis) funcValues(expr ast.Expr) StringSet {
+	result := NewStringSet()
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.FuncLit:
+		if name, ok := ea.funcLits[e]; ok {
+			result.Add(name)
+		}
+	case *ast.Ident:
+		ea.addObjectValues(result, ea.Pass.TypesInfo.ObjectOf(e))
+	case *ast.SelectorExpr:
+		if sel, ok := ea.Pass.TypesInfo.Selections[e]; ok && sel.Kind() != types.FieldVal {
+			// Method values such as repo.Find and method expressions such as (*Repo).Find
+			ea.addObjectValues(result, sel.Obj())
+		} else {
+			ea.addObjectValues(result, ea.assignedObject(e))
+		}
+	case *ast.IndexExpr:
+		// Instantiations of generic functions such as Map[int]
+		return ea.funcValues(e.X)
+	case *ast.IndexListExpr:
+		return ea.funcValues(e.X)
+	case *ast.CallExpr:
+		// A conversion such as Handler(f) holds the converted function
+		if tv, ok := ea.Pass.TypesInfo.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			return ea.funcValues(e.Args[0])
+		}
+		// The call returns whatever its callees return
+		for callee := range ea.callees(e) {
+			result.AddAll(ea.FuncResults[callee])
+		}
+	}
+	return result
+}
+
+// addObjectValues adds the functions held by a variable or named by a function object
+func (ea *EffectAnalysis) addObjectValues(result StringSet, obj types.Object) {
+	switch obj := obj.(type) {
+	case *types.Var:
+		result.AddAll(ea.FuncVars[varOrigin(obj)])
+	case *types.Func:
+		if info, ok := ea.lookupFunction(obj); ok {
+			result.Add(info.Name)
+		}
+	}
+}
+
+// callArgs returns the arguments of call passed for the callee's parameters,
+// leaving out the receiver passed first to a method expression such as (*T).M(t, x)
+func (ea *EffectAnalysis) callArgs(call *ast.CallExpr) []ast.Expr {
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if s, ok := ea.Pass.TypesInfo.Selections[sel]; ok && s.Kind() == types.MethodExpr && len(call.Args) > 0 {
+			return call.Args[1:]
+		}
+	}
+	return call.Args
+}
+
+// callees returns the names of the functions a call may invoke
+func (ea *EffectAnalysis) callees(call *ast.CallExpr) StringSet {
+	if callee := ea.calleeFunc(call); callee != nil {
+		return NewStringSet(FuncKey(callee))
+	}
+	return ea.funcValues(call.Fun)
+}
+
+// escapingFuncs calls fn for every function value passed at call for a parameter
+// that is not an effect parameter of callee. The callee may call such functions at
+// any time, so they are treated as called at the call site.
+// Functions with a body of this package call them through their parameters instead,
+// and parameters of function types with a contract are checked against it.
+func (ea *EffectAnalysis) escapingFuncs(call *ast.CallExpr, callee *FunctionInfo, fn func(arg ast.Expr, funcName string)) {
+	if callee != nil && callee.Node() != nil {
+		return
+	}
+	var sig *types.Signature
+	if t := ea.Pass.TypesInfo.TypeOf(call.Fun); t != nil {
+		sig, _ = t.Underlying().(*types.Signature)
+	}
+	offset := len(call.Args) - len(ea.callArgs(call))
+	for i, arg := range call.Args {
+		if callee != nil && slices.Contains(callee.EffectParams, i-offset) {
+			continue
+		}
+		if sig != nil {
+			if _, ok := ea.funcContract(nil, argumentType(sig, call, i)); ok {
+				continue
+			}
+		}
+		for _, funcName := range ea.funcValues(arg).ToSlice() {
+			fn(arg, funcName)
+		}
+	}
+}
+
+// isFuncType reports whether t is a function type
+func isFuncType(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Signature)
+	return ok
+}
+
+// varOrigin returns the generic origin of a variable or struct field
+func varOrigin(obj types.Object) types.Object {
+	if v, ok := obj.(*types.Var); ok {
+		return v.Origin()
+	}
+	return obj
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"sort"
+)
+
+// TypeParamCall is a call of a method on a type parameter of a generic function or of
+// the receiver type of a method. In the body it has the effects of the constraint's
+// contract; at each instantiation of an undeclared generic function, or each call of
+// an undeclared method of a generic type, the type argument's method is substituted.
+type TypeParamCall struct {
+	Index  int         // index of the type parameter
+	Method *types.Func // method of the constraint
+}
+
+// collectTypeParamCalls records the calls of methods on type parameters in the
+// bodies of generic functions and of the methods of generic types, including the
+// ones of generic callees instantiated with the caller's own type parameters
+func (ea *EffectAnalysis) collectTypeParamCalls() {
+	var generic []*FunctionInfo
+	for _, info := range ea.Functions {
+		if info.Decl == nil || info.Object == nil {
+			continue
+		}
+		sig := info.Object.Type().(*types.Signature)
+		if sig.TypeParams().Len() == 0 && sig.RecvTypeParams().Len() == 0 {
+			continue
+		}
+		generic = append(generic, info)
+		ea.inspectCalls(info, func(call *ast.CallExpr, _ CallKind) {
+			tp, ok := ea.typeParamRecv(call)
+			callee := ea.calleeFunc(call)
+			if !ok || callee == nil {
+				return
+			}
+			info.TypeParamCalls = append(info.TypeParamCalls, TypeParamCall{Index: tp.Index(), Method: callee})
+		})
+	}
+
+	// Forward the calls of generic callees through chains of generic functions
+	for changed := true; changed; {
+		changed = false
+		for _, info := range generic {
+			ea.inspectCalls(info, func(call *ast.CallExpr, _ CallKind) {
+				for _, tpc := range ea.forwardedTypeParamCalls(info, call) {
+					if !slices.Contains(info.TypeParamCalls, tpc) {
+						info.TypeParamCalls = append(info.TypeParamCalls, tpc)
+						changed = true
+					}
+				}
+			})
+		}
+	}
+
+	for _, info := range generic {
+		if len(info.TypeParamCalls) > 0 && !info.HasDeclaration {
+			info.BaseEffects = NewStringSet()
+		}
+	}
+}
+
+// forwardedTypeParamCalls returns the calls on type parameters of the generic callee
+// at call whose type arguments are type parameters of info, with their indices
+// remapped to the type parameters of info
+func (ea *EffectAnalysis) forwardedTypeParamCalls(info *FunctionInfo, call *ast.CallExpr) []TypeParamCall {
+	callee := ea.calleeFunc(call)
+	if callee == nil || callee.Pkg() != ea.Pass.Pkg {
+		return nil
+	}
+	calleeInfo, ok := ea.Functions[FuncKey(callee)]
+	if !ok || calleeInfo.HasDeclaration || len(calleeInfo.TypeParamCalls) == 0 {
+		return nil
+	}
+	args, ok := ea.typeArgs(call)
+	if !ok {
+		return nil
+	}
+
+	var result []TypeParamCall
+	for _, tpc := range calleeInfo.TypeParamCalls {
+		if tpc.Index >= args.Len() {
+			continue
+		}
+		if tp, ok := args.At(tpc.Index).(*types.TypeParam); ok && ownsTypeParam(info, tp) {
+			result = append(result, TypeParamCall{Index: tp.Index(), Method: tpc.Method})
+		}
+	}
+	return result
+}
+
+// ownsTypeParam reports whether tp is a type parameter of the function or of the
+// receiver type of the method of info
+func ownsTypeParam(info *FunctionInfo, tp *types.TypeParam) bool {
+	sig := info.Object.Type().(*types.Signature)
+	for _, list := range []*types.TypeParamList{sig.TypeParams(), sig.RecvTypeParams()} {
+		if tp.Index() < list.Len() && list.At(tp.Index()) == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// typeParamRecv returns the type parameter whose method is called at call
+func (ea *EffectAnalysis) typeParamRecv(call *ast.CallExpr) (*types.TypeParam, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	selection, ok := ea.Pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, false
+	}
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	tp, ok := recv.(*types.TypeParam)
+	return tp, ok
+}
+
+// instantiateTypeParams returns the methods of the type arguments at call that replace
+// the calls of methods on type parameters in the body of an undeclared generic callee.
+// The caller depends on them, so they are also recorded in the call graph. Methods of
+// type arguments that are themselves type parameters are returned separately: they
+// have the effects of their constraint's contract and are substituted at the caller's
+// own instantiations.
+func (ea *EffectAnalysis) instantiateTypeParams(caller string, callee *FunctionInfo, call *ast.CallExpr) (methods, forwarded []string, ok bool) {
+	if callee.BaseEffects == nil {
+		return nil, nil, false
+	}
+	args, ok := ea.typeArgs(call)
+	if !ok {
+		return nil, nil, false
+	}
+
+	for _, tpc := range callee.TypeParamCalls {
+		if tpc.Index >= args.Len() {
+			continue
+		}
+		arg := args.At(tpc.Index)
+		obj, _, _ := types.LookupFieldOrMethod(arg, true, tpc.Method.Pkg(), tpc.Method.Name())
+		method, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		info, ok := ea.lookupFunction(method)
+		if !ok {
+			continue
+		}
+		if _, ok := arg.(*types.TypeParam); ok {
+			forwarded = append(forwarded, info.Name)
+		} else {
+			methods = append(methods, info.Name)
+		}
+		ea.CallGraph.AddCall(caller, info.Name, call.Pos())
+	}
+	return methods, forwarded, true
+}
+
+// typeArgs returns the type arguments of the generic function called at call, or of
+// the receiver type of the method of a generic type called at call
+func (ea *EffectAnalysis) typeArgs(call *ast.CallExpr) (*types.TypeList, bool) {
+	if inst, ok := ea.instanceOf(call.Fun); ok {
+		return inst.TypeArgs, true
+	}
+	callee := ea.calleeFunc(call)
+	if callee == nil {
+		return nil, false
+	}
+	recv := callee.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil, false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.TypeArgs().Len() == 0 {
+		return nil, false
+	}
+	return named.TypeArgs(), true
+}
+
+// instanceOf returns the instantiation of the generic function referred to by expr
+func (ea *EffectAnalysis) instanceOf(expr ast.Expr) (types.Instance, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.IndexExpr:
+		return ea.instanceOf(e.X)
+	case *ast.IndexListExpr:
+		return ea.instanceOf(e.X)
+	case *ast.SelectorExpr:
+		return ea.instanceOf(e.Sel)
+	case *ast.Ident:
+		inst, ok := ea.Pass.TypesInfo.Instances[e]
+		return inst, ok
+	}
+	return types.Instance{}, false
+}
+
+// checkInstantiations verifies that the type arguments of every instantiation of a
+// generic function or type implement the methods of their constraints within the
+// constraints' effect contracts
+func (ea *EffectAnalysis) checkInstantiations() {
+	idents := make([]*ast.Ident, 0, len(ea.Pass.TypesInfo.Instances))
+	for ident := range ea.Pass.TypesInfo.Instances {
+		idents = append(idents, ident)
+	}
+	sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+
+	for _, ident := range idents {
+		inst := ea.Pass.TypesInfo.Instances[ident]
+		var tparams *types.TypeParamList
+		switch obj := ea.Pass.TypesInfo.Uses[ident].(type) {
+		case *types.Func:
+			tparams = obj.Type().(*types.Signature).TypeParams()
+		case *types.TypeName:
+			if named, ok := obj.Type().(*types.Named); ok {
+				tparams = named.TypeParams()
+			}
+		}
+
+		for i := 0; i < tparams.Len() && i < inst.TypeArgs.Len(); i++ {
+			arg := inst.TypeArgs.At(i)
+			if types.IsInterface(arg) {
+				// Type parameters and interfaces are bound by their own contracts
+				continue
+			}
+			iface, ok := tparams.At(i).Constraint().Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			for j := 0; j < iface.NumMethods(); j++ {
+				ea.checkImplementation(ident, arg, iface.Method(j))
+			}
+		}
+	}
+}
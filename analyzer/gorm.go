@@ -0,0 +1,311 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// gormPackage is the import path of GORM
+const gormPackage = "gorm.io/gorm"
+
+// gormOperations maps the terminal methods of GORM chains to the operations they run
+var gormOperations = map[string][]string{
+	"Find":            {"select"},
+	"FindInBatches":   {"select"},
+	"First":           {"select"},
+	"Last":            {"select"},
+	"Take":            {"select"},
+	"Scan":            {"select"},
+	"Count":           {"select"},
+	"Pluck":           {"select"},
+	"Row":             {"select"},
+	"Rows":            {"select"},
+	"FirstOrInit":     {"select"},
+	"Create":          {"insert"},
+	"CreateInBatches": {"insert"},
+	"FirstOrCreate":   {"select", "insert"},
+	"Save":            {"insert", "update"},
+	"Update":          {"update"},
+	"Updates":         {"update"},
+	"UpdateColumn":    {"update"},
+	"UpdateColumns":   {"update"},
+	"Delete":          {"delete"},
+}
+
+// gormInferer infers database effects from GORM method chains: the operation from
+// the terminal method and the table from Table, Model or the terminal method's value
+type gormInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (gormInferer) Name() string { return "gorm" }
+
+// Infer returns the effects of a call of a terminal method of *gorm.DB, or of the SQL
+// passed to Raw and Exec
+func (gormInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	if !inPackages(callee, gormPackage) || !isGormDB(callee.Type().(*types.Signature).Recv()) {
+		return nil, false
+	}
+	if callee.Name() == "Raw" || callee.Name() == "Exec" {
+		query, ok := ea.stringArg(call, callee, "sql")
+		if !ok {
+			return nil, false
+		}
+		return SQLEffects(query), true
+	}
+
+	ops, ok := gormOperations[callee.Name()]
+	if !ok {
+		return nil, false
+	}
+	table, raw := ea.gormChainTable(call)
+	if raw {
+		// The statement of a Raw chain is inferred from its SQL
+		return NewStringSet(), true
+	}
+	if table == "" {
+		// The value passed to the terminal method: Find(&users) or Create(&order)
+		for _, arg := range call.Args {
+			if table = ea.gormTable(ea.Pass.TypesInfo.TypeOf(arg)); table != "" {
+				break
+			}
+		}
+	}
+	if table == "" {
+		return nil, false
+	}
+
+	effects := NewStringSet()
+	for _, op := range ops {
+		effects.Add(op + "[" + table + "]")
+	}
+	return effects, true
+}
+
+// isGormDB reports whether a receiver is *gorm.DB
+func isGormDB(recv *types.Var) bool {
+	if recv == nil {
+		return false
+	}
+	ptr, ok := recv.Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Name() == "DB"
+}
+
+// gormChainTable returns the table set earlier in the chain of call by Table or Model,
+// and whether the chain starts with Raw
+func (ea *EffectAnalysis) gormChainTable(call *ast.CallExpr) (string, bool) {
+	for {
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		call, ok = ast.Unparen(sel.X).(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		callee := ea.calleeFunc(call)
+		if callee == nil || !inPackages(callee, gormPackage) {
+			return "", false
+		}
+		switch callee.Name() {
+		case "Raw":
+			return "", true
+		case "Table":
+			if name, ok := ea.stringArg(call, callee, "name"); ok {
+				return name, false
+			}
+		case "Model":
+			if len(call.Args) > 0 {
+				if table := ea.gormTable(ea.Pass.TypesInfo.TypeOf(call.Args[0])); table != "" {
+					return table, false
+				}
+			}
+		}
+	}
+}
+
+// gormTable returns the table of a GORM model type, or of the element type of a
+// pointer, slice or array of models: the result of its TableName method or the
+// name given by GORM's default naming strategy
+func (ea *EffectAnalysis) gormTable(t types.Type) string {
+	for {
+		switch u := types.Unalias(t).(type) {
+		case *types.Pointer:
+			t = u.Elem()
+			continue
+		case *types.Slice:
+			t = u.Elem()
+			continue
+		case *types.Array:
+			t = u.Elem()
+			continue
+		}
+		break
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return ""
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return ""
+	}
+	if name, ok := ea.tableName(named.Origin().Obj()); ok {
+		return name
+	}
+	return GormTableName(named.Obj().Name())
+}
+
+// TableNameFact records the constant returned by the TableName method of a model type
+type TableNameFact struct {
+	Name string
+}
+
+// AFact marks TableNameFact as a fact type
+func (*TableNameFact) AFact() {}
+
+// String returns a human-readable representation of the table name
+func (f *TableNameFact) String() string {
+	return fmt.Sprintf("TableNameFact(%s)", f.Name)
+}
+
+// tableName returns the constant returned by the TableName method of a type, declared
+// in this package or known from a fact of the package declaring it
+func (ea *EffectAnalysis) tableName(obj *types.TypeName) (string, bool) {
+	if obj.Pkg() != ea.Pass.Pkg {
+		var fact TableNameFact
+		if ea.DisableFacts || obj.Pkg() == nil || !ea.Pass.ImportObjectFact(obj, &fact) {
+			return "", false
+		}
+		return fact.Name, true
+	}
+
+	method, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), true, obj.Pkg(), "TableName")
+	fn, ok := method.(*types.Func)
+	if !ok {
+		return "", false
+	}
+	info, ok := ea.Functions[FuncKey(fn)]
+	if !ok || info.Decl == nil || info.Decl.Body == nil || len(info.Decl.Body.List) != 1 {
+		return "", false
+	}
+	ret, ok := info.Decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	tv, ok := ea.Pass.TypesInfo.Types[ret.Results[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// ExportTableNames exports the table names of the model types of the package
+func (ea *EffectAnalysis) ExportTableNames() {
+	scope := ea.Pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		if table, ok := ea.tableName(obj); ok {
+			ea.Pass.ExportObjectFact(obj, &TableNameFact{Name: table})
+		}
+	}
+}
+
+// GormTableName returns the table name GORM's default naming strategy gives to a
+// model type: the plural of its name in snake case, "UserProfile" to "user_profiles"
+func GormTableName(typeName string) string {
+	name := snakeCase(typeName)
+	prefix, last := "", name
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		prefix, last = name[:i+1], name[i+1:]
+	}
+	return prefix + pluralize(last)
+}
+
+// snakeCase converts a Go name to snake case, keeping initialisms together:
+// "HTTPServer" to "http_server" and "UserID" to "user_id"
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower && unicode.IsUpper(runes[i-1]) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// pluralRules are the rules of the inflection library used by GORM, most specific first
+var pluralRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(quiz)$`), "${1}zes"},
+	{regexp.MustCompile(`^(oxen)$`), "${1}"},
+	{regexp.MustCompile(`^(ox)$`), "${1}en"},
+	{regexp.MustCompile(`([m|l])ice$`), "${1}ice"},
+	{regexp.MustCompile(`([m|l])ouse$`), "${1}ice"},
+	{regexp.MustCompile(`(matr|vert|ind)(?:ix|ex)$`), "${1}ices"},
+	{regexp.MustCompile(`(x|ch|ss|sh)$`), "${1}es"},
+	{regexp.MustCompile(`([^aeiouy]|qu)y$`), "${1}ies"},
+	{regexp.MustCompile(`(hive)$`), "${1}s"},
+	{regexp.MustCompile(`(?:([^f])fe|([lr])f)$`), "${1}${2}ves"},
+	{regexp.MustCompile(`sis$`), "ses"},
+	{regexp.MustCompile(`([ti])a$`), "${1}a"},
+	{regexp.MustCompile(`([ti])um$`), "${1}a"},
+	{regexp.MustCompile(`(buffal|tomat)o$`), "${1}oes"},
+	{regexp.MustCompile(`(bu)s$`), "${1}ses"},
+	{regexp.MustCompile(`(alias|status|campus)$`), "${1}es"},
+	{regexp.MustCompile(`(octop|vir)(?:us|i)$`), "${1}i"},
+	{regexp.MustCompile(`(ax|test)is$`), "${1}es"},
+	{regexp.MustCompile(`s$`), "s"},
+	{regexp.MustCompile(`$`), "s"},
+}
+
+// irregularPlurals are the irregular plurals of the inflection library
+var irregularPlurals = map[string]string{
+	"person": "people",
+	"man":    "men",
+	"child":  "children",
+	"sex":    "sexes",
+	"move":   "moves",
+	"mombie": "mombies",
+}
+
+// uncountableWords are the words without a plural in the inflection library
+var uncountableWords = map[string]bool{
+	"equipment": true, "information": true, "rice": true, "money": true, "species": true,
+	"series": true, "fish": true, "sheep": true, "jeans": true, "police": true,
+}
+
+// pluralize returns the plural of a lowercase word
+func pluralize(word string) string {
+	if uncountableWords[word] {
+		return word
+	}
+	if plural, ok := irregularPlurals[word]; ok {
+		return plural
+	}
+	for _, rule := range pluralRules {
+		if rule.pattern.MatchString(word) {
+			return rule.pattern.ReplaceAllString(word, rule.replacement)
+		}
+	}
+	return word
+}
@@ -0,0 +1,24 @@
+package analyzer
+
+import "testing"
+
+func TestGormTableName(t *testing.T) {
+	tests := map[string]string{
+		"User":        "users",
+		"OrderItem":   "order_items",
+		"UserID":      "user_ids",
+		"HTTPRequest": "http_requests",
+		"Category":    "categories",
+		"Address":     "addresses",
+		"Person":      "people",
+		"Equipment":   "equipment",
+		"Status":      "statuses",
+		"Wolf":        "wolves",
+		"Analysis":    "analyses",
+	}
+	for typeName, want := range tests {
+		if got := GormTableName(typeName); got != want {
+			t.Errorf("GormTableName(%q) = %q, want %q", typeName, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+)
+
+// EffectInferer infers the effects of calls of library functions from the calls
+// themselves, such as the tables of a SQL query passed as a string constant
+type EffectInferer interface {
+	// Name identifies the inferer in the -infer flag
+	Name() string
+	// Infer returns the effects of call, a call of callee, if callee is a function
+	// the inferer knows and its effects can be determined from the call
+	Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool)
+}
+
+// inferers lists the available effect inferers
+var inferers = []EffectInferer{
+	sqlInferer{},
+	gormInferer{},
+	entInferer{},
+	redisInferer{},
+	kafkaInferer{},
+	natsInferer{},
+}
+
+// DefaultInferers is the default value of the -infer flag
+const DefaultInferers = "sql,gorm,ent,redis,kafka,nats"
+
+// selectInferers returns the inferers named in a comma-separated list
+func selectInferers(names string) ([]EffectInferer, bool) {
+	var result []EffectInferer
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, inferer := range inferers {
+			if inferer.Name() == name {
+				result = append(result, inferer)
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+// inferEffects returns the effects of a call of a library function inferred by the
+// enabled inferers. Results are cached, as each phase resolves calls on its own.
+func (ea *EffectAnalysis) inferEffects(call *ast.CallExpr) (StringSet, bool) {
+	if effects, ok := ea.inferred[call]; ok {
+		return effects, effects != nil
+	}
+
+	var effects StringSet
+	callee := ea.calleeFunc(call)
+	if callee != nil && callee.Pkg() != nil && callee.Pkg() != ea.Pass.Pkg {
+		for _, inferer := range ea.Inferers {
+			if inferred, ok := inferer.Infer(ea, call, callee); ok {
+				debugLog("Inferred %v for %s by %s", inferred.ToSlice(), FuncKey(callee), inferer.Name())
+				if effects == nil {
+					effects = NewStringSet()
+				}
+				effects.AddAll(inferred)
+			}
+		}
+	}
+	ea.inferred[call] = effects
+	return effects, effects != nil
+}
+
+// inferredCallee returns the function info for the callee of a call with inferred
+// effects. Functions without effects of their own from Facts or JSON declarations
+// get an effect-free one, so that the call's effects are all inferred.
+func (ea *EffectAnalysis) inferredCallee(callee *types.Func) *FunctionInfo {
+	if info, ok := ea.lookupFunction(callee); ok {
+		return info
+	}
+	info := &FunctionInfo{
+		Name:            FuncKey(callee),
+		Package:         callee.Pkg().Path(),
+		DeclaredEffects: NewStringSet(),
+		ComputedEffects: NewStringSet(),
+		HasDeclaration:  true,
+		CallSites:       []CallSite{},
+	}
+	ea.Functions[info.Name] = info
+	return info
+}
+
+// stringArg returns the value of the string parameter of callee named like one of
+// names at call, if it is a constant. Constant expressions such as concatenations
+// of constants are evaluated by the type checker.
+func (ea *EffectAnalysis) stringArg(call *ast.CallExpr, callee *types.Func, names ...string) (string, bool) {
+	sig := callee.Type().(*types.Signature)
+	args := ea.callArgs(call)
+	for i := 0; i < sig.Params().Len() && i < len(args); i++ {
+		param := sig.Params().At(i)
+		basic, ok := param.Type().Underlying().(*types.Basic)
+		if !ok || basic.Kind() != types.String {
+			continue
+		}
+		for _, name := range names {
+			if param.Name() != name {
+				continue
+			}
+			tv, ok := ea.Pass.TypesInfo.Types[args[i]]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+				return "", false
+			}
+			return constant.StringVal(tv.Value), true
+		}
+	}
+	return "", false
+}
+
+// inPackages reports whether fn belongs to one of the packages or, for paths ending
+// in "/", to a package under one of the prefixes
+func inPackages(fn *types.Func, paths ...string) bool {
+	if fn.Pkg() == nil {
+		return false
+	}
+	pkgPath := fn.Pkg().Path()
+	for _, path := range paths {
+		if pkgPath == path || strings.HasSuffix(path, "/") && strings.HasPrefix(pkgPath, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// validTarget reports whether an inferred name can be the target of an effect label:
+// an identifier of effect declarations, such as "users" or "orders.created"
+func validTarget(name string) bool {
+	if name == "" || !isLetter([]rune(name)[0]) {
+		return false
+	}
+	for _, r := range name {
+		if !isLetter(r) && !isDigit(r) && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// initName returns the name of the package initialization, "pkg.init".
+// The init functions of the package are named "pkg.init#1", "pkg.init#2", ...
+func (ea *EffectAnalysis) initName() string {
+	return ea.Pass.Pkg.Path() + ".init"
+}
+
+// collectInit registers the package initialization as an analysis unit. It runs the
+// package-level variable initializers and the init functions, after the initialization
+// of the imported packages. A // dirty: comment in the package doc declares its effects.
+func (ea *EffectAnalysis) collectInit() {
+	info := &FunctionInfo{
+		Name:            ea.initName(),
+		Package:         ea.Pass.Pkg.Path(),
+		DeclaredEffects: NewStringSet(),
+		ComputedEffects: NewStringSet(),
+		CallSites:       []CallSite{},
+	}
+
+	var doc *ast.CommentGroup
+	for _, file := range ea.Pass.Files {
+		if declComment(file.Doc) != nil && doc == nil {
+			doc = file.Doc
+		}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				info.Inits = append(info.Inits, gen)
+			}
+		}
+	}
+	ea.applyDeclaration(info, doc)
+
+	ea.Functions[info.Name] = info
+	ea.Resolver.AddLocalFunction(info.Name, info)
+}
+
+// buildInitCalls records the calls of the package initialization to the init functions
+// and to the initialization of the imported packages, whose effects are known from Facts
+func (ea *EffectAnalysis) buildInitCalls() {
+	info := ea.Functions[ea.initName()]
+
+	for _, fn := range ea.Functions {
+		if fn.Decl != nil && strings.HasPrefix(fn.Name, info.Name+"#") {
+			ea.addCallSite(info, CallSite{Callee: fn.Name, Position: fn.Decl.Name.Pos()})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range ea.Pass.Files {
+		for _, spec := range file.Imports {
+			pkgName := ea.Pass.TypesInfo.PkgNameOf(spec)
+			if pkgName == nil || seen[pkgName.Imported().Path()] {
+				continue
+			}
+			path := pkgName.Imported().Path()
+			seen[path] = true
+
+			calleeName := path + ".init"
+			if _, source := ea.Resolver.ResolveEffects(calleeName); source != SourceFacts {
+				continue
+			}
+			if _, ok := ea.importedDecl(calleeName, path, nil); ok {
+				ea.addCallSite(info, CallSite{Callee: calleeName, Position: spec.Pos()})
+			}
+		}
+	}
+}
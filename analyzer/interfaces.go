@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// collectInterfaceMethods registers the methods of an interface type declaration.
+// A // dirty: comment on an interface method declares the effect contract that
+// callers through the interface get and that implementations must respect.
+func (ea *EffectAnalysis) collectInterfaceMethods(spec *ast.TypeSpec) {
+	iface, ok := spec.Type.(*ast.InterfaceType)
+	if !ok || iface.Methods == nil {
+		return
+	}
+
+	for _, field := range iface.Methods.List {
+		// Embedded interfaces and type constraints have no names
+		for _, name := range field.Names {
+			obj, ok := ea.Pass.TypesInfo.Defs[name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			info := &FunctionInfo{
+				Name:            FuncKey(obj),
+				Package:         ea.Pass.Pkg.Path(),
+				DeclaredEffects: NewStringSet(),
+				ComputedEffects: NewStringSet(),
+				Abstract:        true,
+				Object:          obj,
+				CallSites:       []CallSite{},
+			}
+			ea.applyDeclaration(info, field.Doc)
+
+			// Methods without a contract are not tracked
+			if !info.HasDeclaration {
+				continue
+			}
+
+			ea.Functions[info.Name] = info
+			ea.Resolver.AddLocalFunction(info.Name, info)
+		}
+	}
+}
+
+// CheckInterfaceImplementations verifies that concrete types assigned to an interface
+// implement its methods within their effect contracts.
+// This covers assignments, struct fields, returned values, channel sends and the
+// elements of slices and maps as well as assertions like var _ I = (*T)(nil), and the
+// type arguments of generic functions and types constrained by the interface.
+func (ea *EffectAnalysis) CheckInterfaceImplementations() {
+	ea.forEachAssignment(func(a assignment) {
+		src := ea.Pass.TypesInfo.TypeOf(a.Src)
+		if src == nil || types.IsInterface(src) {
+			return
+		}
+		if _, ok := src.(*types.Tuple); ok {
+			// Multi-value assignments such as v, err := f()
+			return
+		}
+		iface, ok := a.Dst.Underlying().(*types.Interface)
+		if !ok {
+			return
+		}
+
+		for i := 0; i < iface.NumMethods(); i++ {
+			ea.checkImplementation(a.Src, src, iface.Method(i))
+		}
+	})
+
+	// Type arguments implement the constraints of their type parameters
+	ea.checkInstantiations()
+}
+
+// checkImplementation reports when the method of src implementing method
+// has effects beyond the contract of method
+func (ea *EffectAnalysis) checkImplementation(expr ast.Expr, src types.Type, method *types.Func) {
+	contractName := FuncKey(method)
+	contract, ok := ea.effectsOf(contractName)
+	if !ok {
+		return
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(src, true, method.Pkg(), method.Name())
+	impl, ok := obj.(*types.Func)
+	if !ok {
+		return
+	}
+	implName := FuncKey(impl)
+	effects, ok := ea.effectsOf(implName)
+	if !ok || ea.Lattice.CoversAll(contract, effects) {
+		return
+	}
+
+	missing := ea.Lattice.Uncovered(contract, effects)
+	ea.Pass.Reportf(expr.Pos(),
+		"method %s has effects [%s] not declared in the contract of %s%s",
+		ea.displayName(implName), joinEffects(missing.ToSlice()),
+		ea.displayName(contractName), wildcardNote(contract, missing))
+}
@@ -0,0 +1,316 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultOperations is the default value of the -operations flag
+const DefaultOperations = "write=insert|update|delete"
+
+// EffectLattice orders effect labels by inclusion. A declared label covers the
+// labels it includes:
+//   - its wildcards: select[*] includes select[users], *[users] includes delete[users]
+//   - the descendants of its target in the dotted hierarchy: select[db.users]
+//     includes select[db.users.email]
+//   - the operations its operation implies: write[users] includes insert[users]
+type EffectLattice struct {
+	// implied maps operations to the operations they imply, transitively
+	implied map[string]StringSet
+	// children maps operations to the operations they directly imply
+	children map[string][]string
+}
+
+// NewEffectLattice creates a lattice from an operation hierarchy mapping operations
+// to the operations they directly imply. The hierarchy must not have cycles.
+func NewEffectLattice(hierarchy map[string][]string) (*EffectLattice, error) {
+	l := &EffectLattice{implied: make(map[string]StringSet), children: hierarchy}
+
+	ops := make([]string, 0, len(hierarchy))
+	for op := range hierarchy {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var visit func(op string, path []string) error
+	visit = func(op string, path []string) error {
+		if _, ok := l.implied[op]; ok {
+			return nil
+		}
+		for i, other := range path {
+			if other == op {
+				return fmt.Errorf("operation %s implies itself: %s", op, strings.Join(append(path[i:], op), " -> "))
+			}
+		}
+		implied := NewStringSet()
+		for _, sub := range hierarchy[op] {
+			if err := visit(sub, append(path, op)); err != nil {
+				return err
+			}
+			implied.Add(sub)
+			implied.AddAll(l.implied[sub])
+		}
+		l.implied[op] = implied
+		return nil
+	}
+	for _, op := range ops {
+		if err := visit(op, nil); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// ParseOperationHierarchy parses an operation hierarchy given as comma-separated
+// entries such as "write=insert|update|delete,read=select"
+func ParseOperationHierarchy(spec string) (map[string][]string, error) {
+	hierarchy := make(map[string][]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		op, subs, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected 'operation=sub|sub' in %q", entry)
+		}
+		op = strings.TrimSpace(op)
+		if !validOperation(op) {
+			return nil, fmt.Errorf("invalid operation %q", op)
+		}
+		for _, sub := range strings.Split(subs, "|") {
+			sub = strings.TrimSpace(sub)
+			if !validOperation(sub) {
+				return nil, fmt.Errorf("invalid operation %q", sub)
+			}
+			hierarchy[op] = append(hierarchy[op], sub)
+		}
+	}
+	return hierarchy, nil
+}
+
+// validOperation reports whether name can be the operation of an effect label
+func validOperation(name string) bool {
+	tok := NewLexer(name).NextToken()
+	return tok.Type == TokenIdent && tok.Value == name
+}
+
+// Includes reports whether the declared label covers an effect. Only TopEffect
+// covers itself.
+func (l *EffectLattice) Includes(declared, effect string) bool {
+	if declared == effect {
+		return true
+	}
+	if effect == TopEffect || declared == TopEffect {
+		return false
+	}
+	declOp, declTarget := splitLabel(declared)
+	op, target := splitLabel(effect)
+	return l.includesOperation(declOp, op) && includesTarget(declTarget, target)
+}
+
+// includesOperation reports whether a declared operation covers op
+func (l *EffectLattice) includesOperation(declared, op string) bool {
+	return declared == Wildcard || declared == op || l.implied[declared].Contains(op)
+}
+
+// includesTarget reports whether a declared target covers target: the wildcard covers
+// every target and labels without one, and a dotted path covers the paths below it
+func includesTarget(declared, target string) bool {
+	if declared == Wildcard || declared == target {
+		return true
+	}
+	return declared != "" && strings.HasPrefix(target, declared+".")
+}
+
+// Covers returns the label of declared covering effect: the effect itself if
+// declared contains it, or the first label including it
+func (l *EffectLattice) Covers(declared StringSet, effect string) (string, bool) {
+	if declared.Contains(effect) {
+		return effect, true
+	}
+	for _, label := range declared.ToSlice() {
+		if l.Includes(label, effect) {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// CoversAll reports whether every effect of effects is covered by a label of declared
+func (l *EffectLattice) CoversAll(declared, effects StringSet) bool {
+	return len(l.Uncovered(declared, effects)) == 0
+}
+
+// Uncovered returns the effects of effects not covered by a label of declared
+func (l *EffectLattice) Uncovered(declared, effects StringSet) StringSet {
+	result := make(StringSet)
+	for effect := range effects {
+		if _, ok := l.Covers(declared, effect); !ok {
+			result[effect] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Overlaps reports whether two labels may denote a common effect: one includes the
+// other, or their wildcards and hierarchies meet, as *[users] and delete[*] do at
+// delete[users]. A label without a target overlaps every target of its operation, so
+// network overlaps network[mailer]. TopEffect, the unknown effects of strict mode,
+// overlaps every label.
+func (l *EffectLattice) Overlaps(a, b string) bool {
+	if a == b || a == TopEffect || b == TopEffect {
+		return true
+	}
+	aOp, aTarget := splitLabel(a)
+	bOp, bTarget := splitLabel(b)
+	return l.overlapsOperation(aOp, bOp) && overlapsTarget(aTarget, bTarget)
+}
+
+// overlapsTarget reports whether two targets may denote a common target: one covers
+// the other, or either is missing
+func overlapsTarget(a, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+	return includesTarget(a, b) || includesTarget(b, a)
+}
+
+// overlapsOperation reports whether two operations imply a common operation
+func (l *EffectLattice) overlapsOperation(a, b string) bool {
+	if l.includesOperation(a, b) || l.includesOperation(b, a) {
+		return true
+	}
+	return len(l.implied[a].Intersection(l.implied[b])) > 0
+}
+
+// Overlapping returns the effects of effects overlapping a label of labels
+func (l *EffectLattice) Overlapping(labels, effects StringSet) StringSet {
+	result := make(StringSet)
+	for effect := range effects {
+		for label := range labels {
+			if l.Overlaps(label, effect) {
+				result[effect] = struct{}{}
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Intersection returns the labels covering the effects both a and b cover: for each
+// pair of labels, the narrower one, or the labels they share, as select[users] for
+// *[users] and select[*]
+func (l *EffectLattice) Intersection(a, b StringSet) StringSet {
+	result := make(StringSet)
+	for x := range a {
+		for y := range b {
+			result.AddAll(l.meet(x, y))
+		}
+	}
+	return result
+}
+
+// meet returns the labels covering the effects both x and y cover
+func (l *EffectLattice) meet(x, y string) StringSet {
+	switch {
+	case l.Includes(x, y):
+		return NewStringSet(y)
+	case l.Includes(y, x):
+		return NewStringSet(x)
+	case x == TopEffect || y == TopEffect:
+		return NewStringSet()
+	}
+
+	xOp, xTarget := splitLabel(x)
+	yOp, yTarget := splitLabel(y)
+	target := ""
+	switch {
+	case includesTarget(xTarget, yTarget):
+		target = yTarget
+	case includesTarget(yTarget, xTarget):
+		target = xTarget
+	default:
+		return NewStringSet()
+	}
+	var ops []string
+	switch {
+	case l.includesOperation(xOp, yOp):
+		ops = []string{yOp}
+	case l.includesOperation(yOp, xOp):
+		ops = []string{xOp}
+	default:
+		ops = l.implied[xOp].Intersection(l.implied[yOp]).ToSlice()
+	}
+
+	result := NewStringSet()
+	for _, op := range ops {
+		result.Add(makeLabel(op, target))
+	}
+	return result
+}
+
+// Difference returns the labels covering the effects a covers and b does not. Labels
+// of a partly covered by b are split into the operations they imply, write[users]
+// into insert[users], update[users] and delete[users]. Wildcards and targets cannot
+// be split, so removing delete[users] from *[users] is an error.
+func (l *EffectLattice) Difference(a, b StringSet) (StringSet, error) {
+	result := make(StringSet)
+	for _, x := range a.ToSlice() {
+		if err := l.subtract(x, b, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// subtract adds to result the labels covering the effects x covers and b does not
+func (l *EffectLattice) subtract(x string, b StringSet, result StringSet) error {
+	var partial []string
+	for _, y := range b.ToSlice() {
+		if l.Includes(y, x) {
+			return nil
+		}
+		if len(l.meet(x, y)) > 0 {
+			partial = append(partial, y)
+		}
+	}
+	if len(partial) == 0 {
+		result.Add(x)
+		return nil
+	}
+
+	op, target := splitLabel(x)
+	children := l.children[op]
+	if x == TopEffect || len(children) == 0 {
+		return fmt.Errorf("cannot remove %s from %s", partial[0], x)
+	}
+	for _, child := range children {
+		if err := l.subtract(makeLabel(child, target), b, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeLabel returns the effect label of an operation on a target, if any
+func makeLabel(op, target string) string {
+	if target == "" {
+		return op
+	}
+	return op + "[" + target + "]"
+}
+
+// latticeResolver is implemented by effect resolvers knowing the operation hierarchy
+type latticeResolver interface {
+	Lattice() *EffectLattice
+}
+
+// latticeOf returns the lattice of a resolver, or one without an operation hierarchy
+func latticeOf(resolver EffectResolver) *EffectLattice {
+	if r, ok := resolver.(latticeResolver); ok && r.Lattice() != nil {
+		return r.Lattice()
+	}
+	return &EffectLattice{implied: make(map[string]StringSet)}
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEffectLatticeIncludes(t *testing.T) {
+	hierarchy, err := ParseOperationHierarchy("write=insert|modify, modify=update|delete")
+	if err != nil {
+		t.Fatalf("ParseOperationHierarchy() error = %v", err)
+	}
+	lattice, err := NewEffectLattice(hierarchy)
+	if err != nil {
+		t.Fatalf("NewEffectLattice() error = %v", err)
+	}
+
+	tests := []struct {
+		declared string
+		effect   string
+		want     bool
+	}{
+		{"select[users]", "select[users]", true},
+		{"select[users]", "insert[users]", false},
+		{"select[*]", "select[users]", true},
+		{"select[*]", "select", true},
+		{"*[users]", "delete[users]", true},
+		{"*[*]", "network", true},
+		{"*[*]", TopEffect, false},
+		{"select[users]", "select[*]", false},
+		{"select[db]", "select[db.users.email]", true},
+		{"select[db.users]", "select[db.usersettings]", false},
+		{"select[db.users]", "select[db]", false},
+		{"network", "network[api]", false},
+		{"write[users]", "insert[users]", true},
+		{"write[users]", "delete[users]", true},
+		{"write[db]", "update[db.users]", true},
+		{"modify[users]", "insert[users]", false},
+		{"insert[users]", "write[users]", false},
+		{"write", "delete", true},
+	}
+	for _, tt := range tests {
+		if got := lattice.Includes(tt.declared, tt.effect); got != tt.want {
+			t.Errorf("Includes(%q, %q) = %v, want %v", tt.declared, tt.effect, got, tt.want)
+		}
+	}
+}
+
+func TestEffectLatticeCovers(t *testing.T) {
+	lattice, err := NewEffectLattice(map[string][]string{"write": {"insert", "update"}})
+	if err != nil {
+		t.Fatalf("NewEffectLattice() error = %v", err)
+	}
+	declared := NewStringSet("select[users]", "select[*]", "write[db]")
+
+	for effect, want := range map[string]string{
+		"select[users]":      "select[users]",
+		"select[orders]":     "select[*]",
+		"insert[db.users]":   "write[db]",
+		"delete[db.users]":   "",
+		"update[cache.user]": "",
+	} {
+		got, ok := lattice.Covers(declared, effect)
+		if got != want || ok != (want != "") {
+			t.Errorf("Covers(%q) = %q, %v, want %q", effect, got, ok, want)
+		}
+	}
+
+	uncovered := lattice.Uncovered(declared, NewStringSet("select[orders]", "delete[db.users]", "update[db]"))
+	if got := uncovered.ToSlice(); !equalStringSlices(got, []string{"delete[db.users]"}) {
+		t.Errorf("Uncovered() = %v, want [delete[db.users]]", got)
+	}
+}
+
+func TestEffectLatticeOverlaps(t *testing.T) {
+	lattice, err := NewEffectLattice(map[string][]string{"write": {"insert", "update"}, "modify": {"update", "delete"}})
+	if err != nil {
+		t.Fatalf("NewEffectLattice() error = %v", err)
+	}
+
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"network", "network", true},
+		{"network", "network[api]", true},
+		{"network", "select[users]", false},
+		{"delete[*]", "*[users]", true},
+		{"delete[*]", "select[users]", false},
+		{"select[db.users]", "select[db]", true},
+		{"select[db.users]", "select[db.orders]", false},
+		{"write[users]", "modify[users]", true},
+		{"insert[users]", "modify[users]", false},
+		{"network", TopEffect, true},
+	}
+	for _, tt := range tests {
+		if got := lattice.Overlaps(tt.a, tt.b); got != tt.want {
+			t.Errorf("Overlaps(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := lattice.Overlaps(tt.b, tt.a); got != tt.want {
+			t.Errorf("Overlaps(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestEffectLatticeSetOperators(t *testing.T) {
+	lattice, err := NewEffectLattice(map[string][]string{"write": {"insert", "modify"}, "modify": {"update", "delete"}})
+	if err != nil {
+		t.Fatalf("NewEffectLattice() error = %v", err)
+	}
+
+	intersections := []struct {
+		a, b []string
+		want []string
+	}{
+		{[]string{"select[*]"}, []string{"select[users]"}, []string{"select[users]"}},
+		{[]string{"*[users]"}, []string{"delete[*]"}, []string{"delete[users]"}},
+		{[]string{"select[db]"}, []string{"select[db.users.email]", "select[cache]"}, []string{"select[db.users.email]"}},
+		{[]string{"write[users]"}, []string{"delete[users]", "select[users]"}, []string{"delete[users]"}},
+		{[]string{"network"}, []string{"network[api]"}, []string{}},
+	}
+	for _, tt := range intersections {
+		got := lattice.Intersection(NewStringSet(tt.a...), NewStringSet(tt.b...)).ToSlice()
+		if !equalStringSlices(got, tt.want) {
+			t.Errorf("Intersection(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	differences := []struct {
+		a, b    []string
+		want    []string
+		wantErr string
+	}{
+		{a: []string{"select[users]", "delete[users]"}, b: []string{"delete[users]"}, want: []string{"select[users]"}},
+		{a: []string{"select[db.users]"}, b: []string{"select[db]"}, want: []string{}},
+		{a: []string{"write[users]"}, b: []string{"delete[users]"}, want: []string{"insert[users]", "update[users]"}},
+		{a: []string{"write[users]"}, b: []string{"modify[*]"}, want: []string{"insert[users]"}},
+		{a: []string{"*[users]"}, b: []string{"delete[users]"}, wantErr: "cannot remove delete[users] from *[users]"},
+		{a: []string{"select[db]"}, b: []string{"select[db.users]"}, wantErr: "cannot remove select[db.users] from select[db]"},
+		{a: []string{"write[db]"}, b: []string{"delete[db.users]"}, wantErr: "cannot remove delete[db.users] from delete[db]"},
+	}
+	for _, tt := range differences {
+		got, err := lattice.Difference(NewStringSet(tt.a...), NewStringSet(tt.b...))
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Difference(%v, %v) error = %v, want %q", tt.a, tt.b, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Difference(%v, %v) error = %v", tt.a, tt.b, err)
+			continue
+		}
+		if !equalStringSlices(got.ToSlice(), tt.want) {
+			t.Errorf("Difference(%v, %v) = %v, want %v", tt.a, tt.b, got.ToSlice(), tt.want)
+		}
+	}
+}
+
+func TestParseOperationHierarchy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: ""},
+		{spec: DefaultOperations},
+		{spec: "write=insert|update, read=select"},
+		{spec: "write", wantErr: "expected 'operation=sub|sub'"},
+		{spec: "write=insert|", wantErr: `invalid operation ""`},
+		{spec: "write[users]=insert", wantErr: `invalid operation "write[users]"`},
+		{spec: "write=modify,modify=write", wantErr: "operation modify implies itself: modify -> write -> modify"},
+	}
+	for _, tt := range tests {
+		hierarchy, err := ParseOperationHierarchy(tt.spec)
+		if err == nil {
+			_, err = NewEffectLattice(hierarchy)
+		}
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", tt.spec, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%q: error = %v, want %q", tt.spec, err, tt.wantErr)
+		}
+	}
+}
@@ -10,16 +10,19 @@ type TokenType int
 
 // Token types for the effect language parser
 const (
-	TokenEOF      TokenType = iota
-	TokenLBrace             // {
-	TokenRBrace             // }
-	TokenLParen             // (
-	TokenRParen             // )
-	TokenPipe               // |
-	TokenLBracket           // [
-	TokenRBracket           // ]
-	TokenIdent              // identifier
-	TokenIllegal            // illegal token
+	TokenEOF       TokenType = iota
+	TokenLBrace              // {
+	TokenRBrace              // }
+	TokenLParen              // (
+	TokenRParen              // )
+	TokenPipe                // |
+	TokenBackslash           // \
+	TokenAmp                 // &
+	TokenStar                // *
+	TokenLBracket            // [
+	TokenRBracket            // ]
+	TokenIdent               // identifier
+	TokenIllegal             // illegal token
 )
 
 // Token represents a lexical token
@@ -98,6 +101,18 @@ func (l *Lexer) NextToken() Token {
 		tok.Type = TokenPipe
 		tok.Value = "|"
 		l.readChar()
+	case '\\':
+		tok.Type = TokenBackslash
+		tok.Value = "\\"
+		l.readChar()
+	case '&':
+		tok.Type = TokenAmp
+		tok.Value = "&"
+		l.readChar()
+	case '*':
+		tok.Type = TokenStar
+		tok.Value = "*"
+		l.readChar()
 	case '[':
 		tok.Type = TokenLBracket
 		tok.Value = "["
@@ -148,6 +163,12 @@ func TokenString(t TokenType) string {
 		return ")"
 	case TokenPipe:
 		return "|"
+	case TokenBackslash:
+		return "\\"
+	case TokenAmp:
+		return "&"
+	case TokenStar:
+		return "*"
 	case TokenLBracket:
 		return "["
 	case TokenRBracket:
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// FuncKey returns the fully qualified name that identifies fn in the call graph,
+// in Facts and in effect registries.
+// Functions are named "pkg/path.Func", methods "pkg/path.(*T).M" or "pkg/path.T.M".
+func FuncKey(fn *types.Func) string {
+	fn = fn.Origin()
+	name := fn.Name()
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		name = receiverName(sig.Recv().Type()) + "." + name
+	}
+	if fn.Pkg() == nil {
+		// Universe scope methods such as error.Error
+		return name
+	}
+	return fn.Pkg().Path() + "." + name
+}
+
+// receiverName formats a receiver type as "T" or "(*T)"
+func receiverName(t types.Type) string {
+	pointer := false
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+		pointer = true
+	}
+
+	name := "interface"
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		name = named.Obj().Name()
+	}
+
+	if pointer {
+		return "(*" + name + ")"
+	}
+	return name
+}
+
+// RelativeName strips the package path from a qualified name when it belongs to pkgPath.
+// "example.com/db.(*Repo).Find" relative to "example.com/db" is "(*Repo).Find".
+func RelativeName(qualifiedName, pkgPath string) string {
+	if rel, ok := strings.CutPrefix(qualifiedName, pkgPath+"."); ok {
+		return rel
+	}
+	return qualifiedName
+}
+
+// displayName returns the name used for a function in diagnostics
+func (ea *EffectAnalysis) displayName(funcName string) string {
+	if ea.Pass.Pkg == nil {
+		return funcName
+	}
+	return RelativeName(funcName, ea.Pass.Pkg.Path())
+}
+
+// calleeFunc returns the function or method called by call, resolved through type information.
+// Methods promoted through embedded fields, at any depth, resolve to the method of
+// the embedded type or interface.
+// It returns nil for calls of function values, builtins and conversions.
+func (ea *EffectAnalysis) calleeFunc(call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(ea.Pass.TypesInfo, call).(*types.Func)
+	return fn
+}
+
+// promotedThrough returns the names of the embedded fields through which the method
+// called by call is promoted, outermost first. It is empty for methods declared on
+// the receiver's own type.
+func (ea *EffectAnalysis) promotedThrough(call *ast.CallExpr) []string {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	selection, ok := ea.Pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil
+	}
+
+	var fields []string
+	t := selection.Recv()
+	index := selection.Index()
+	for _, i := range index[:len(index)-1] {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		field := st.Field(i)
+		fields = append(fields, field.Name())
+		t = field.Type()
+	}
+	return fields
+}
@@ -46,8 +46,179 @@ func ParseEffectDecl(comment string) (EffectExpr, error) {
 		return &LiteralSet{Elements: []EffectExpr{}}, nil
 	}
 
+	// Text after "//" is a comment
+	if i := strings.Index(content, "//"); i >= 0 {
+		content = strings.TrimSpace(content[:i])
+	}
+
 	parser := NewParser(content)
-	return parser.parseSetExpr()
+	expr, err := parser.parseUnion(true)
+	if err != nil {
+		return nil, err
+	}
+
+	// An optional async clause declares the effects of spawned goroutines
+	if parser.cur.Type == TokenIdent && parser.cur.Value == "async" {
+		parser.nextToken() // skip async
+		async, err := parser.parseUnion(true)
+		if err != nil {
+			return nil, err
+		}
+		expr = &AsyncDecl{Sync: expr, Async: async}
+	}
+	if err := parser.expectEOF(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// ParseForbiddenEffects parses the effects of a //dirty-forbid: comment, such as
+// "// dirty-forbid: { network | delete[*] }". It has no async clause.
+func ParseForbiddenEffects(comment string) (EffectExpr, error) {
+	comment = strings.TrimSpace(comment)
+	content := strings.TrimPrefix(comment, "//dirty-forbid:")
+	if content == comment {
+		content = strings.TrimPrefix(comment, "// dirty-forbid:")
+	}
+
+	expr, err := ParseEffectDecl("//dirty: " + content)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := expr.(*AsyncDecl); ok {
+		return nil, fmt.Errorf("unexpected async clause")
+	}
+	return expr, nil
+}
+
+// ParseEffectDefinition parses a named effect set definition and returns its name
+// and expression. The input is a comment such as "//dirty-define: userOps = { select[users] }",
+// optionally followed by another comment.
+func ParseEffectDefinition(comment string) (string, EffectExpr, error) {
+	comment = strings.TrimSpace(comment)
+	content := strings.TrimPrefix(comment, "//dirty-define:")
+	if content == comment {
+		content = strings.TrimPrefix(comment, "// dirty-define:")
+	}
+
+	name, body, ok := strings.Cut(content, "=")
+	if !ok {
+		return "", nil, fmt.Errorf("expected 'name = { ... }'")
+	}
+	name = strings.TrimSpace(name)
+	lexer := NewLexer(name)
+	if tok := lexer.NextToken(); tok.Type != TokenIdent || tok.Value != name || strings.Contains(name, ".") {
+		return "", nil, fmt.Errorf("invalid name %q", name)
+	}
+
+	// Text after "//" is a comment
+	if i := strings.Index(body, "//"); i >= 0 {
+		body = body[:i]
+	}
+	parser := NewParser(strings.TrimSpace(body))
+	expr, err := parser.parseUnion(true)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := parser.expectEOF(); err != nil {
+		return "", nil, err
+	}
+	return name, expr, nil
+}
+
+// expectEOF reports tokens left after a declaration
+func (p *Parser) expectEOF() error {
+	if p.cur.Type != TokenEOF {
+		return fmt.Errorf("unexpected token at position %d: %s", p.cur.Pos, p.cur.String())
+	}
+	return nil
+}
+
+// parseUnion parses operands separated by '|', the loosest operator. At the top level
+// of a declaration, operands are sets, references and parenthesized expressions;
+// labels such as select[users] must be in braces.
+func (p *Parser) parseUnion(top bool) (EffectExpr, error) {
+	elements := []EffectExpr{}
+	for {
+		elem, err := p.parseDifference(top)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, elem)
+
+		if p.cur.Type != TokenPipe {
+			break
+		}
+		p.nextToken() // skip |
+	}
+
+	// If only one element, return it directly
+	if len(elements) == 1 {
+		return elements[0], nil
+	}
+
+	// Otherwise, wrap in a literal set
+	return &LiteralSet{Elements: elements}, nil
+}
+
+// parseDifference parses operands separated by '\', which binds tighter than '|'
+// and associates to the left: a \ b \ c is (a \ b) \ c
+func (p *Parser) parseDifference(top bool) (EffectExpr, error) {
+	left, err := p.parseIntersection(top)
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.Type == TokenBackslash {
+		p.nextToken() // skip \
+		right, err := p.parseIntersection(top)
+		if err != nil {
+			return nil, err
+		}
+		left = &DifferenceExpr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseIntersection parses operands separated by '&', the tightest operator
+func (p *Parser) parseIntersection(top bool) (EffectExpr, error) {
+	left, err := p.parseOperand(top)
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.Type == TokenAmp {
+		p.nextToken() // skip &
+		right, err := p.parseOperand(top)
+		if err != nil {
+			return nil, err
+		}
+		left = &IntersectionExpr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseOperand parses an operand of the set operators
+func (p *Parser) parseOperand(top bool) (EffectExpr, error) {
+	if !top {
+		return p.parsePrimary()
+	}
+	switch {
+	case p.cur.Type == TokenLBrace:
+		return p.parseSetExpr()
+	case p.cur.Type == TokenLParen:
+		p.nextToken() // skip (
+		expr, err := p.parseUnion(true)
+		if err != nil {
+			return nil, err
+		}
+		if p.cur.Type != TokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d, got %s", p.cur.Pos, p.cur.String())
+		}
+		p.nextToken() // skip )
+		return expr, nil
+	case p.cur.Type == TokenIdent && p.peek.Type != TokenLBracket:
+		return p.parsePrimary()
+	}
+	return nil, fmt.Errorf("expected '{' at position %d, got %s", p.cur.Pos, p.cur.String())
 }
 
 // parseSetExpr parses a set expression: { ... }
@@ -66,7 +237,7 @@ func (p *Parser) parseSetExpr() (EffectExpr, error) {
 	// Parse elements
 	elements := []EffectExpr{}
 	for {
-		elem, err := p.parsePrimary()
+		elem, err := p.parseDifference(false)
 		if err != nil {
 			return nil, err
 		}
@@ -92,16 +263,16 @@ func (p *Parser) parseSetExpr() (EffectExpr, error) {
 // parsePrimary parses a primary expression
 func (p *Parser) parsePrimary() (EffectExpr, error) {
 	switch p.cur.Type {
-	case TokenIdent:
+	case TokenIdent, TokenStar:
 		// Could be an effect label or effect reference
 		ident := p.cur.Value
 		p.nextToken()
 
 		if p.cur.Type == TokenLBracket {
-			// Effect label: operation[target]
+			// Effect label: operation[target], either of which may be the wildcard *
 			p.nextToken() // skip [
 
-			if p.cur.Type != TokenIdent {
+			if p.cur.Type != TokenIdent && p.cur.Type != TokenStar {
 				return nil, fmt.Errorf("expected identifier after '[' at position %d, got %s", p.cur.Pos, p.cur.String())
 			}
 			target := p.cur.Value
@@ -118,6 +289,10 @@ func (p *Parser) parsePrimary() (EffectExpr, error) {
 			}, nil
 		}
 
+		if ident == Wildcard {
+			return nil, fmt.Errorf("expected '[' after '*' at position %d, got %s", p.cur.Pos, p.cur.String())
+		}
+
 		// Treat bare identifier as effect label without target
 		// e.g., "transform" becomes EffectLabel{Operation: "transform", Target: ""}
 		return &EffectLabel{
@@ -125,10 +300,13 @@ func (p *Parser) parsePrimary() (EffectExpr, error) {
 			Target:    "",
 		}, nil
 
+	case TokenLBrace:
+		return p.parseSetExpr()
+
 	case TokenLParen:
 		// Parenthesized expression
 		p.nextToken() // skip (
-		expr, err := p.parseUnionExpr()
+		expr, err := p.parseUnion(false)
 		if err != nil {
 			return nil, err
 		}
@@ -142,29 +320,3 @@ func (p *Parser) parsePrimary() (EffectExpr, error) {
 		return nil, fmt.Errorf("unexpected token at position %d: %s", p.cur.Pos, p.cur.String())
 	}
 }
-
-// parseUnionExpr parses a union expression (for future use with parentheses)
-func (p *Parser) parseUnionExpr() (EffectExpr, error) {
-	elements := []EffectExpr{}
-
-	for {
-		elem, err := p.parsePrimary()
-		if err != nil {
-			return nil, err
-		}
-		elements = append(elements, elem)
-
-		if p.cur.Type != TokenPipe {
-			break
-		}
-		p.nextToken() // skip |
-	}
-
-	// If only one element, return it directly
-	if len(elements) == 1 {
-		return elements[0], nil
-	}
-
-	// Otherwise, wrap in a literal set
-	return &LiteralSet{Elements: elements}, nil
-}
@@ -77,7 +77,119 @@ func TestParseEffectDecl(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "with async clause",
+			input: "//dirty: { insert[users] } async { network[mailer] }",
+			want: &AsyncDecl{
+				Sync: &LiteralSet{
+					Elements: []EffectExpr{
+						&EffectLabel{Operation: "insert", Target: "users"},
+					},
+				},
+				Async: &LiteralSet{
+					Elements: []EffectExpr{
+						&EffectLabel{Operation: "network", Target: "mailer"},
+					},
+				},
+			},
+		},
+		{
+			name:  "with empty async clause",
+			input: "//dirty: { select[users] } async { }",
+			want: &AsyncDecl{
+				Sync: &LiteralSet{
+					Elements: []EffectExpr{
+						&EffectLabel{Operation: "select", Target: "users"},
+					},
+				},
+				Async: &LiteralSet{Elements: []EffectExpr{}},
+			},
+		},
+		{
+			name:  "difference",
+			input: "//dirty: userOps \\ { delete[users] }",
+			want: &DifferenceExpr{
+				Left:  &EffectLabel{Operation: "userOps"},
+				Right: &LiteralSet{Elements: []EffectExpr{&EffectLabel{Operation: "delete", Target: "users"}}},
+			},
+		},
+		{
+			name:  "intersection",
+			input: "//dirty: userOps & adminOps",
+			want: &IntersectionExpr{
+				Left:  &EffectLabel{Operation: "userOps"},
+				Right: &EffectLabel{Operation: "adminOps"},
+			},
+		},
+		{
+			name:  "precedence",
+			input: "//dirty: a | b \\ c & d",
+			want: &LiteralSet{Elements: []EffectExpr{
+				&EffectLabel{Operation: "a"},
+				&DifferenceExpr{
+					Left: &EffectLabel{Operation: "b"},
+					Right: &IntersectionExpr{
+						Left:  &EffectLabel{Operation: "c"},
+						Right: &EffectLabel{Operation: "d"},
+					},
+				},
+			}},
+		},
+		{
+			name:  "difference is left-associative",
+			input: "//dirty: a \\ b \\ c",
+			want: &DifferenceExpr{
+				Left: &DifferenceExpr{
+					Left:  &EffectLabel{Operation: "a"},
+					Right: &EffectLabel{Operation: "b"},
+				},
+				Right: &EffectLabel{Operation: "c"},
+			},
+		},
+		{
+			name:  "parentheses",
+			input: "//dirty: (a | b) & c",
+			want: &IntersectionExpr{
+				Left: &LiteralSet{Elements: []EffectExpr{
+					&EffectLabel{Operation: "a"},
+					&EffectLabel{Operation: "b"},
+				}},
+				Right: &EffectLabel{Operation: "c"},
+			},
+		},
+		{
+			name:  "operators in a set",
+			input: "//dirty: { userOps \\ delete[users] | insert[logs] }",
+			want: &LiteralSet{Elements: []EffectExpr{
+				&DifferenceExpr{
+					Left:  &EffectLabel{Operation: "userOps"},
+					Right: &EffectLabel{Operation: "delete", Target: "users"},
+				},
+				&EffectLabel{Operation: "insert", Target: "logs"},
+			}},
+		},
+		{
+			name:  "trailing comment",
+			input: "//dirty: { select[users] } // reads only",
+			want: &LiteralSet{Elements: []EffectExpr{
+				&EffectLabel{Operation: "select", Target: "users"},
+			}},
+		},
+		{
+			name:  "wildcards",
+			input: "//dirty: { select[*] | *[users] | *[*] }",
+			want: &LiteralSet{Elements: []EffectExpr{
+				&EffectLabel{Operation: "select", Target: "*"},
+				&EffectLabel{Operation: "*", Target: "users"},
+				&EffectLabel{Operation: "*", Target: "*"},
+			}},
+		},
 		// Error cases
+		{
+			name:    "wildcard without target",
+			input:   "//dirty: { select[users] | * }",
+			wantErr: true,
+		},
 		{
 			name:    "missing opening brace",
 			input:   "//dirty: select[users] }",
@@ -98,9 +210,29 @@ func TestParseEffectDecl(t *testing.T) {
 			input:   "//dirty: { select[users }",
 			wantErr: true,
 		},
+		{
+			name:    "async clause without set",
+			input:   "//dirty: { select[users] } async",
+			wantErr: true,
+		},
 		{
 			name:    "invalid token",
-			input:   "//dirty: { select[users] & insert[logs] }",
+			input:   "//dirty: { select[users] ; insert[logs] }",
+			wantErr: true,
+		},
+		{
+			name:    "missing operand",
+			input:   "//dirty: { select[users] } \\",
+			wantErr: true,
+		},
+		{
+			name:    "label operand without braces",
+			input:   "//dirty: userOps \\ delete[users]",
+			wantErr: true,
+		},
+		{
+			name:    "trailing token",
+			input:   "//dirty: { select[users] } }",
 			wantErr: true,
 		},
 	}
@@ -150,6 +282,34 @@ func TestEval(t *testing.T) {
 			},
 			want: []string{"select[users]", "insert[logs]", "update[users]"},
 		},
+		{
+			name: "difference",
+			expr: &DifferenceExpr{
+				Left: &LiteralSet{Elements: []EffectExpr{
+					&EffectLabel{Operation: "select", Target: "users"},
+					&EffectLabel{Operation: "delete", Target: "users"},
+				}},
+				Right: &LiteralSet{Elements: []EffectExpr{
+					&EffectLabel{Operation: "delete", Target: "users"},
+					&EffectLabel{Operation: "insert", Target: "logs"},
+				}},
+			},
+			want: []string{"select[users]"},
+		},
+		{
+			name: "intersection",
+			expr: &IntersectionExpr{
+				Left: &LiteralSet{Elements: []EffectExpr{
+					&EffectLabel{Operation: "select", Target: "users"},
+					&EffectLabel{Operation: "delete", Target: "users"},
+				}},
+				Right: &LiteralSet{Elements: []EffectExpr{
+					&EffectLabel{Operation: "delete", Target: "users"},
+					&EffectLabel{Operation: "insert", Target: "logs"},
+				}},
+			},
+			want: []string{"delete[users]"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -189,3 +349,178 @@ func equalStringSlices(a, b []string) bool {
 	}
 	return true
 }
+
+func TestEffectExprString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"//dirty: userOps \\ { delete[users] }", "userOps \\ { delete[users] }"},
+		{"//dirty: a | b \\ c & d", "{ a | b \\ c & d }"},
+		{"//dirty: (a | b) & c", "{ a | b } & c"},
+		{"//dirty: (a \\ b) & c", "(a \\ b) & c"},
+		{"//dirty: a \\ (b \\ c)", "a \\ (b \\ c)"},
+		{"//dirty: (a \\ b) \\ c", "a \\ b \\ c"},
+		{"//dirty: { a & b } async c \\ d", "{ a & b } async c \\ d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseEffectDecl(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEffectDecl() error = %v", err)
+			}
+			if got := expr.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			// The printed expression parses back to the same one
+			again, err := ParseEffectDecl("//dirty: " + expr.String())
+			if err != nil {
+				t.Fatalf("ParseEffectDecl(String()) error = %v", err)
+			}
+			if !reflect.DeepEqual(again, expr) {
+				t.Errorf("ParseEffectDecl(String()) = %v, want %v", again, expr)
+			}
+		})
+	}
+}
+
+func TestBindEffectVars(t *testing.T) {
+	expr, err := ParseEffectDecl("//dirty: { e | begin[tx] | transform }")
+	if err != nil {
+		t.Fatalf("ParseEffectDecl() error = %v", err)
+	}
+
+	got := BindEffectVars(expr, map[string]bool{"e": true, "tx": true})
+	want := &LiteralSet{
+		Elements: []EffectExpr{
+			&EffectVar{Name: "e"},
+			&EffectLabel{Operation: "begin", Target: "tx"},
+			&EffectLabel{Operation: "transform", Target: ""},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindEffectVars() = %v, want %v", got, want)
+	}
+
+	if vars := EffectVars(got).ToSlice(); !equalStringSlices(vars, []string{"e"}) {
+		t.Errorf("EffectVars() = %v, want [e]", vars)
+	}
+
+	effects, err := got.Eval(nil)
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if !equalStringSlices(effects.ToSlice(), []string{"begin[tx]", "transform"}) {
+		t.Errorf("Eval() = %v, want [begin[tx] transform]", effects.ToSlice())
+	}
+}
+
+func TestParseEffectDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantName string
+		want     EffectExpr
+		wantErr  bool
+	}{
+		{
+			name:     "definition",
+			input:    "//dirty-define: userOps = { select[users] | update[users] }",
+			wantName: "userOps",
+			want: &LiteralSet{
+				Elements: []EffectExpr{
+					&EffectLabel{Operation: "select", Target: "users"},
+					&EffectLabel{Operation: "update", Target: "users"},
+				},
+			},
+		},
+		{
+			name:     "with space and trailing comment",
+			input:    "// dirty-define: Ops = { userOps } // shared by handlers",
+			wantName: "Ops",
+			want: &LiteralSet{
+				Elements: []EffectExpr{
+					&EffectLabel{Operation: "userOps", Target: ""},
+				},
+			},
+		},
+		// Error cases
+		{
+			name:    "missing name",
+			input:   "//dirty-define: = { select[users] }",
+			wantErr: true,
+		},
+		{
+			name:    "qualified name",
+			input:   "//dirty-define: db.Ops = { select[users] }",
+			wantErr: true,
+		},
+		{
+			name:    "missing set",
+			input:   "//dirty-define: userOps = select[users]",
+			wantErr: true,
+		},
+		{
+			name:    "trailing tokens",
+			input:   "//dirty-define: userOps = { select[users] } }",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, got, err := ParseEffectDefinition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseEffectDefinition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (name != tt.wantName || !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("ParseEffectDefinition() = %s, %v, want %s, %v", name, got, tt.wantName, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindEffectRefs(t *testing.T) {
+	expr, err := ParseEffectDecl("//dirty: { userOps | db.UserOps | network | select[users] }")
+	if err != nil {
+		t.Fatalf("ParseEffectDecl() error = %v", err)
+	}
+
+	got := BindEffectRefs(expr, func(name string) bool { return name != "network" })
+	want := &LiteralSet{
+		Elements: []EffectExpr{
+			&EffectRef{Name: "userOps"},
+			&EffectRef{Name: "db.UserOps"},
+			&EffectLabel{Operation: "network", Target: ""},
+			&EffectLabel{Operation: "select", Target: "users"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindEffectRefs() = %v, want %v", got, want)
+	}
+}
+
+func TestParseForbiddenEffects(t *testing.T) {
+	expr, err := ParseForbiddenEffects("// dirty-forbid: { network | delete[*] }")
+	if err != nil {
+		t.Fatalf("ParseForbiddenEffects() error = %v", err)
+	}
+	want := &LiteralSet{Elements: []EffectExpr{
+		&EffectLabel{Operation: "network"},
+		&EffectLabel{Operation: "delete", Target: "*"},
+	}}
+	if !reflect.DeepEqual(expr, want) {
+		t.Errorf("ParseForbiddenEffects() = %v, want %v", expr, want)
+	}
+
+	for _, input := range []string{
+		"//dirty-forbid: network[*]",
+		"//dirty-forbid: { network } async { exec }",
+	} {
+		if _, err := ParseForbiddenEffects(input); err == nil {
+			t.Errorf("ParseForbiddenEffects(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// bindEffectParams binds the effect variables of a declaration to the function-typed
+// parameters of the same name and records them as the effect parameters of info
+func (ea *EffectAnalysis) bindEffectParams(info *FunctionInfo, expr EffectExpr) EffectExpr {
+	if info.Object == nil {
+		return expr
+	}
+	sig, ok := info.Object.Type().(*types.Signature)
+	if !ok {
+		return expr
+	}
+	expr, info.EffectParams = bindSignatureParams(sig, expr)
+	return expr
+}
+
+// bindSignatureParams binds target-less labels named after function-typed parameters
+// of sig as effect variables. It returns the bound expression and the indices of the
+// parameters that are used as effect variables.
+func bindSignatureParams(sig *types.Signature, expr EffectExpr) (EffectExpr, []int) {
+	params := sig.Params()
+	vars := make(map[string]bool)
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		if _, ok := param.Type().Underlying().(*types.Signature); ok && param.Name() != "" {
+			vars[param.Name()] = true
+		}
+	}
+	if len(vars) == 0 {
+		return expr, nil
+	}
+
+	expr = BindEffectVars(expr, vars)
+	used := EffectVars(expr)
+
+	var indices []int
+	for i := 0; i < params.Len(); i++ {
+		if used.Contains(params.At(i).Name()) {
+			indices = append(indices, i)
+		}
+	}
+	return expr, indices
+}
+
+// instantiateEffectParams returns the functions passed at call for the effect parameters
+// of callee. The caller depends on them, so they are also recorded in the call graph.
+func (ea *EffectAnalysis) instantiateEffectParams(caller string, callee *FunctionInfo, call *ast.CallExpr) []string {
+	var result []string
+	args := ea.callArgs(call)
+	for _, i := range callee.EffectParams {
+		if i >= len(args) {
+			continue
+		}
+		arg := args[i]
+		for _, name := range ea.funcValues(arg).ToSlice() {
+			result = append(result, name)
+			ea.CallGraph.AddCall(caller, name, arg.Pos())
+		}
+	}
+	return result
+}
+
+// callSiteEffects returns the effects a call site contributes to its caller:
+// the callee's effects, instantiated with the effects of the functions passed
+// for its effect parameters and of the methods of its type arguments
+func (ea *EffectAnalysis) callSiteEffects(call CallSite) (StringSet, bool) {
+	callee, ok := ea.Functions[call.Callee]
+	if !ok {
+		return nil, false
+	}
+	effects := callee.ComputedEffects
+	if call.Substituted {
+		effects = callee.BaseEffects
+	}
+	if len(call.Instantiations) == 0 && call.Inferred == nil {
+		return effects, true
+	}
+
+	effects = effects.Union(call.Inferred)
+	for _, name := range call.Instantiations {
+		if arg, ok := ea.Functions[name]; ok {
+			effects.AddAll(arg.ComputedEffects)
+		}
+	}
+	return effects, true
+}
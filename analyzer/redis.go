@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+)
+
+// redisPackages lists the import paths of go-redis. Paths ending in "/" match every
+// major version.
+var redisPackages = []string{
+	"github.com/redis/go-redis/",
+	"github.com/go-redis/redis",
+	"github.com/go-redis/redis/",
+}
+
+// redisOperations maps go-redis commands to the operations they run on their keys
+var redisOperations = map[string][]string{
+	"Get":              {"cache_read"},
+	"MGet":             {"cache_read"},
+	"Exists":           {"cache_read"},
+	"TTL":              {"cache_read"},
+	"PTTL":             {"cache_read"},
+	"Type":             {"cache_read"},
+	"StrLen":           {"cache_read"},
+	"GetRange":         {"cache_read"},
+	"HGet":             {"cache_read"},
+	"HGetAll":          {"cache_read"},
+	"HMGet":            {"cache_read"},
+	"HExists":          {"cache_read"},
+	"HKeys":            {"cache_read"},
+	"HVals":            {"cache_read"},
+	"HLen":             {"cache_read"},
+	"LRange":           {"cache_read"},
+	"LLen":             {"cache_read"},
+	"LIndex":           {"cache_read"},
+	"SMembers":         {"cache_read"},
+	"SIsMember":        {"cache_read"},
+	"SCard":            {"cache_read"},
+	"ZRange":           {"cache_read"},
+	"ZRangeWithScores": {"cache_read"},
+	"ZRevRange":        {"cache_read"},
+	"ZScore":           {"cache_read"},
+	"ZRank":            {"cache_read"},
+	"ZCard":            {"cache_read"},
+	"ZCount":           {"cache_read"},
+	"Set":              {"cache_write"},
+	"SetNX":            {"cache_write"},
+	"SetXX":            {"cache_write"},
+	"SetEx":            {"cache_write"},
+	"Del":              {"cache_write"},
+	"Unlink":           {"cache_write"},
+	"Expire":           {"cache_write"},
+	"ExpireAt":         {"cache_write"},
+	"PExpire":          {"cache_write"},
+	"Persist":          {"cache_write"},
+	"Incr":             {"cache_write"},
+	"IncrBy":           {"cache_write"},
+	"IncrByFloat":      {"cache_write"},
+	"Decr":             {"cache_write"},
+	"DecrBy":           {"cache_write"},
+	"Append":           {"cache_write"},
+	"HSet":             {"cache_write"},
+	"HSetNX":           {"cache_write"},
+	"HMSet":            {"cache_write"},
+	"HDel":             {"cache_write"},
+	"HIncrBy":          {"cache_write"},
+	"LPush":            {"cache_write"},
+	"RPush":            {"cache_write"},
+	"LPop":             {"cache_write"},
+	"RPop":             {"cache_write"},
+	"LRem":             {"cache_write"},
+	"LTrim":            {"cache_write"},
+	"SAdd":             {"cache_write"},
+	"SRem":             {"cache_write"},
+	"SPop":             {"cache_write"},
+	"ZAdd":             {"cache_write"},
+	"ZRem":             {"cache_write"},
+	"ZIncrBy":          {"cache_write"},
+	"GetSet":           {"cache_read", "cache_write"},
+	"GetDel":           {"cache_read", "cache_write"},
+	"GetEx":            {"cache_read", "cache_write"},
+}
+
+// redisInferer infers cache effects from go-redis commands: the operation from the
+// command and the target from the prefix of its keys, "user" for "user:42"
+type redisInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (redisInferer) Name() string { return "redis" }
+
+// Infer returns the effects of a command of a go-redis client, pipeline or transaction
+// whose keys have known prefixes. Commands queued in pipelines have their effects at
+// the queueing call.
+func (redisInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	if !inPackages(callee, redisPackages...) {
+		return nil, false
+	}
+	ops, ok := redisOperations[callee.Name()]
+	if !ok {
+		return nil, false
+	}
+	keys := ea.redisKeys(call, callee)
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	effects := NewStringSet()
+	for _, key := range keys {
+		prefix, ok := ea.redisKeyPrefix(key)
+		if !ok {
+			return nil, false
+		}
+		for _, op := range ops {
+			effects.Add(op + "[" + prefix + "]")
+		}
+	}
+	return effects, true
+}
+
+// redisKeys returns the arguments of call for the "key" and "keys" parameters of callee
+func (ea *EffectAnalysis) redisKeys(call *ast.CallExpr, callee *types.Func) []ast.Expr {
+	sig := callee.Type().(*types.Signature)
+	args := ea.callArgs(call)
+	var keys []ast.Expr
+	for i := 0; i < sig.Params().Len() && i < len(args); i++ {
+		switch sig.Params().At(i).Name() {
+		case "key":
+			keys = append(keys, args[i])
+		case "keys":
+			if sig.Variadic() && i == sig.Params().Len()-1 {
+				if call.Ellipsis.IsValid() {
+					// The keys of a spread slice are unknown
+					return nil
+				}
+				keys = append(keys, args[i:]...)
+			}
+		}
+	}
+	return keys
+}
+
+// redisKeyPrefix returns the prefix of a key: the part before the first ":" of a
+// string constant, of the constant left operand of a concatenation or of the format
+// of fmt.Sprintf. Keys without a ":" are their own prefix when they are constant.
+func (ea *EffectAnalysis) redisKeyPrefix(key ast.Expr) (string, bool) {
+	text, complete := ea.redisKeyText(key)
+	prefix, _, found := strings.Cut(text, ":")
+	if !found && !complete {
+		return "", false
+	}
+	if !validTarget(prefix) {
+		return "", false
+	}
+	return prefix, true
+}
+
+// redisKeyText returns the constant beginning of a key expression and whether it is
+// the whole key
+func (ea *EffectAnalysis) redisKeyText(key ast.Expr) (string, bool) {
+	key = ast.Unparen(key)
+	if tv, ok := ea.Pass.TypesInfo.Types[key]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+
+	switch e := key.(type) {
+	case *ast.BinaryExpr:
+		text, _ := ea.redisKeyText(e.X)
+		return text, false
+	case *ast.CallExpr:
+		callee := ea.calleeFunc(e)
+		if callee == nil || !inPackages(callee, "fmt") || callee.Name() != "Sprintf" {
+			return "", false
+		}
+		format, ok := ea.stringArg(e, callee, "format")
+		if !ok {
+			return "", false
+		}
+		if i := strings.IndexByte(format, '%'); i >= 0 {
+			return format[:i], false
+		}
+		return format, true
+	}
+	return "", false
+}
@@ -0,0 +1,306 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+)
+
+// sqlPackages lists the database packages whose query and exec methods take SQL.
+// Paths ending in "/" match every package under them.
+var sqlPackages = []string{
+	"database/sql",
+	"github.com/jmoiron/sqlx",
+	"github.com/jackc/pgx/",
+	"github.com/jackc/pgconn",
+}
+
+// sqlInferer infers database effects from the SQL passed to database/sql, sqlx and pgx
+type sqlInferer struct{}
+
+// Name identifies the inferer in the -infer flag
+func (sqlInferer) Name() string { return "sql" }
+
+// Infer returns the effects of the SQL statement passed to a query, exec or prepare
+// function as its "query" or "sql" parameter, if it is a constant
+func (sqlInferer) Infer(ea *EffectAnalysis, call *ast.CallExpr, callee *types.Func) (StringSet, bool) {
+	if !inPackages(callee, sqlPackages...) {
+		return nil, false
+	}
+	query, ok := ea.stringArg(call, callee, "query", "sql")
+	if !ok {
+		return nil, false
+	}
+	return SQLEffects(query), true
+}
+
+// sqlToken is a token of a SQL statement
+type sqlToken struct {
+	text   string // Lowercased for unquoted words
+	word   bool   // Unquoted word: a keyword or an identifier
+	quoted bool   // Quoted identifier
+}
+
+// is reports whether the token is the keyword or punctuation s
+func (t sqlToken) is(s string) bool {
+	return !t.quoted && t.text == s
+}
+
+// name reports whether the token can be an identifier
+func (t sqlToken) name() bool {
+	return t.word || t.quoted
+}
+
+// sqlClauseWords are keywords that may follow a table name, so they are not aliases
+var sqlClauseWords = map[string]bool{
+	"where": true, "join": true, "inner": true, "left": true, "right": true, "full": true,
+	"outer": true, "cross": true, "natural": true, "on": true, "using": true, "group": true,
+	"order": true, "having": true, "limit": true, "offset": true, "union": true, "except": true,
+	"intersect": true, "set": true, "values": true, "select": true, "returning": true,
+	"for": true, "window": true, "fetch": true, "default": true, "as": true,
+}
+
+// SQLEffects returns the database effects of the SQL statements in query:
+// select[t] for tables read by FROM, JOIN and USING, insert[t], update[t] and delete[t]
+// for the targets of INSERT, UPDATE and DELETE and for the actions of MERGE, and
+// delete[t] for TRUNCATE.
+// Names of common table expressions are not tables, and table names lose their schema.
+func SQLEffects(query string) StringSet {
+	tokens := tokenizeSQL(query)
+	effects := NewStringSet()
+	ctes := sqlCTENames(tokens)
+
+	// table returns the table named at i, if any, and the index after the name.
+	// Names followed by parentheses are table functions, except for the target of
+	// INSERT with its column list.
+	table := func(i int, columns bool) (string, int) {
+		for i < len(tokens) && (tokens[i].is("only") || tokens[i].is("lateral")) {
+			i++
+		}
+		if i >= len(tokens) || !tokens[i].name() {
+			return "", i
+		}
+		name := tokens[i].text
+		i++
+		for i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].name() {
+			name = tokens[i+1].text
+			i += 2
+		}
+		if !columns && i < len(tokens) && tokens[i].is("(") || ctes[name] {
+			// Table functions and common table expressions
+			return "", i
+		}
+		return name, i
+	}
+	add := func(op string, i int) (string, int) {
+		name, next := table(i, op == "insert")
+		// Quoted names such as "Order Items" cannot be declared, so they are skipped
+		if validTarget(name) {
+			effects.Add(op + "[" + name + "]")
+		}
+		return name, next
+	}
+
+	// selectList adds select effects for a list of tables with optional aliases starting
+	// at i, and returns the index after it
+	selectList := func(i int) int {
+		for {
+			_, i = add("select", i)
+			if i < len(tokens) && tokens[i].is("as") {
+				i++
+			}
+			if i < len(tokens) && tokens[i].name() && !sqlClauseWords[tokens[i].text] {
+				i++
+			}
+			if i >= len(tokens) || !tokens[i].is(",") {
+				return i
+			}
+			i++
+		}
+	}
+
+	// Keywords are only looked at outside of parentheses or in subqueries, so that
+	// expressions like EXTRACT(YEAR FROM t) are skipped
+	var parens []bool
+	inSubquery := func() bool { return len(parens) == 0 || parens[len(parens)-1] }
+	insertTable, mergeTable := "", ""
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.is("("):
+			parens = append(parens, i+1 < len(tokens) && (tokens[i+1].is("select") || tokens[i+1].is("with") ||
+				tokens[i+1].is("insert") || tokens[i+1].is("update") || tokens[i+1].is("delete")))
+			continue
+		case t.is(")"):
+			if len(parens) > 0 {
+				parens = parens[:len(parens)-1]
+			}
+			continue
+		case t.is(";"):
+			insertTable, mergeTable = "", ""
+			continue
+		}
+		if !t.word || !inSubquery() {
+			continue
+		}
+
+		prev := ""
+		if i > 0 && tokens[i-1].word {
+			prev = tokens[i-1].text
+		}
+		switch t.text {
+		case "from":
+			if prev == "delete" {
+				_, i = add("delete", i+1)
+				i--
+				continue
+			}
+			i = selectList(i+1) - 1
+		case "using":
+			// Sources of DELETE ... USING and MERGE ... USING. The column lists of
+			// JOIN ... USING (id) have no table.
+			i = selectList(i+1) - 1
+		case "join":
+			_, i = add("select", i+1)
+			i--
+		case "into":
+			switch prev {
+			case "insert", "ignore", "replace":
+				insertTable, i = add("insert", i+1)
+				i--
+			case "merge":
+				// The actions of the WHEN clauses apply to the target
+				mergeTable, i = table(i+1, false)
+				i--
+			}
+		case "insert", "delete":
+			// MERGE actions: WHEN NOT MATCHED THEN INSERT and WHEN MATCHED THEN DELETE
+			if prev == "then" && validTarget(mergeTable) {
+				effects.Add(t.text + "[" + mergeTable + "]")
+			}
+		case "update":
+			switch prev {
+			case "then":
+				// MERGE action: WHEN MATCHED THEN UPDATE SET
+				if validTarget(mergeTable) {
+					effects.Add("update[" + mergeTable + "]")
+				}
+			case "do", "key":
+				// Upserts: ON CONFLICT DO UPDATE and ON DUPLICATE KEY UPDATE
+				if insertTable != "" {
+					effects.Add("update[" + insertTable + "]")
+				}
+			case "for", "no":
+				// Row locks: FOR UPDATE and FOR NO KEY UPDATE
+			default:
+				j := i + 1
+				for j < len(tokens) && (tokens[j].is("low_priority") || tokens[j].is("ignore")) {
+					j++
+				}
+				_, i = add("update", j)
+				i--
+			}
+		case "truncate":
+			j := i + 1
+			if j < len(tokens) && tokens[j].is("table") {
+				j++
+			}
+			_, i = add("delete", j)
+			i--
+		}
+	}
+	return effects
+}
+
+// sqlCTENames returns the names of common table expressions: names followed by AS (
+// or by a column list and AS (
+func sqlCTENames(tokens []sqlToken) map[string]bool {
+	names := make(map[string]bool)
+	for i, t := range tokens {
+		if !t.name() {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].is("(") {
+			for j < len(tokens) && !tokens[j].is(")") {
+				j++
+			}
+			j++
+		}
+		if j+1 < len(tokens) && tokens[j].is("as") && tokens[j+1].is("(") {
+			names[t.text] = true
+		}
+	}
+	return names
+}
+
+// tokenizeSQL splits a SQL statement into words, quoted identifiers and punctuation.
+// String literals, numbers, parameters and comments are dropped.
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i-1] == '*' && runes[i] == '/') {
+				i++
+			}
+			i++
+		case r == '\'':
+			// String literals, with '' as an escaped quote
+			i++
+			for i < len(runes) {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+		case r == '"' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			tokens = append(tokens, sqlToken{text: string(runes[i+1 : min(end, len(runes))]), quoted: true})
+			i = end + 1
+		case r == '$' || r == '?' || r == ':' && i+1 < len(runes) && isSQLWordRune(runes[i+1]):
+			// Parameters: $1, ?, :name and casts ::type
+			i++
+			for i < len(runes) && (isSQLWordRune(runes[i]) || runes[i] == ':') {
+				i++
+			}
+		case unicode.IsDigit(r):
+			for i < len(runes) && (isSQLWordRune(runes[i]) || runes[i] == '.') {
+				i++
+			}
+		case isSQLWordRune(r):
+			start := i
+			for i < len(runes) && isSQLWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{text: strings.ToLower(string(runes[start:i])), word: true})
+		default:
+			tokens = append(tokens, sqlToken{text: string(r)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// isSQLWordRune reports whether r can be part of an unquoted SQL word
+func isSQLWordRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQLEffects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "select with joins",
+			query: "SELECT u.id FROM users u INNER JOIN orgs AS o ON o.id = u.org_id LEFT JOIN teams t USING (org_id)",
+			want:  []string{"select[orgs]", "select[teams]", "select[users]"},
+		},
+		{
+			name:  "comma separated tables",
+			query: "SELECT * FROM users u, orgs WHERE u.org_id = orgs.id",
+			want:  []string{"select[orgs]", "select[users]"},
+		},
+		{
+			name:  "insert with select",
+			query: "INSERT INTO archived_users (id, name) SELECT id, name FROM users WHERE deleted",
+			want:  []string{"insert[archived_users]", "select[users]"},
+		},
+		{
+			name:  "update with row lock",
+			query: "UPDATE accounts SET balance = balance - $1 WHERE id = $2; SELECT id FROM ledger FOR UPDATE",
+			want:  []string{"select[ledger]", "update[accounts]"},
+		},
+		{
+			name:  "delete with subquery",
+			query: "DELETE FROM sessions WHERE user_id IN (SELECT id FROM users WHERE banned)",
+			want:  []string{"delete[sessions]", "select[users]"},
+		},
+		{
+			name:  "delete using",
+			query: "DELETE FROM sessions s USING users u WHERE s.user_id = u.id AND u.banned",
+			want:  []string{"delete[sessions]", "select[users]"},
+		},
+		{
+			name:  "merge",
+			query: "MERGE INTO accounts a USING staged_accounts s ON a.id = s.id WHEN MATCHED AND s.closed THEN DELETE WHEN MATCHED THEN UPDATE SET balance = s.balance WHEN NOT MATCHED THEN INSERT (id, balance) VALUES (s.id, s.balance)",
+			want:  []string{"delete[accounts]", "insert[accounts]", "select[staged_accounts]", "update[accounts]"},
+		},
+		{
+			name:  "merge with subquery source",
+			query: "MERGE INTO accounts USING (SELECT id, balance FROM staged_accounts) s ON accounts.id = s.id WHEN MATCHED THEN UPDATE SET balance = s.balance",
+			want:  []string{"select[staged_accounts]", "update[accounts]"},
+		},
+		{
+			name:  "upsert",
+			query: "INSERT INTO members (id) VALUES (?) ON DUPLICATE KEY UPDATE seen = NOW()",
+			want:  []string{"insert[members]", "update[members]"},
+		},
+		{
+			name:  "common table expressions",
+			query: "WITH recent (id) AS (SELECT id FROM orders WHERE created_at > now()) SELECT * FROM recent JOIN items ON items.order_id = recent.id",
+			want:  []string{"select[items]", "select[orders]"},
+		},
+		{
+			name:  "functions, literals and comments",
+			query: "SELECT EXTRACT(YEAR FROM created_at), 'FROM secrets' FROM users -- FROM logs\n/* JOIN audit */",
+			want:  []string{"select[users]"},
+		},
+		{
+			name:  "schemas and quoted names",
+			query: `SELECT * FROM public."Users" JOIN ` + "`billing`.`invoices`" + ` ON true`,
+			want:  []string{"select[Users]", "select[invoices]"},
+		},
+		{
+			name:  "names that are not targets",
+			query: `SELECT * FROM "Order Items" JOIN orders ON true`,
+			want:  []string{"select[orders]"},
+		},
+		{
+			name:  "truncate",
+			query: "TRUNCATE TABLE sessions",
+			want:  []string{"delete[sessions]"},
+		},
+		{
+			name:  "table functions",
+			query: "SELECT * FROM generate_series(1, 10)",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SQLEffects(tt.query).ToSlice()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLEffects(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Call graph backends selected with the -callgraph flag
+const (
+	// CallGraphSyntax resolves dynamic calls syntactically through FuncVars and contracts
+	CallGraphSyntax = "syntax"
+	// CallGraphCHA resolves dynamic calls with class hierarchy analysis over SSA
+	CallGraphCHA = "cha"
+	// CallGraphVTA resolves dynamic calls with variable type analysis over SSA
+	CallGraphVTA = "vta"
+)
+
+// validCallGraphMode reports whether mode is one of the call graph backends
+func validCallGraphMode(mode string) bool {
+	switch mode {
+	case CallGraphSyntax, CallGraphCHA, CallGraphVTA:
+		return true
+	}
+	return false
+}
+
+// BuildSSACallGraph resolves the dynamic calls of the package with a call graph built
+// over SSA, for the CHA and VTA backends. Static calls, propagation and checking are
+// shared with the syntactic backend: only the targets of calls through interfaces,
+// closures and function values come from the SSA call graph.
+func (ea *EffectAnalysis) BuildSSACallGraph() {
+	if ea.CallGraphMode != CallGraphCHA && ea.CallGraphMode != CallGraphVTA {
+		return
+	}
+	// Standard library and dependency packages are analyzed only for their Facts:
+	// their bodies are not built, their dynamic calls are resolved syntactically
+	if inStandardLibrary(ea.Pass) || inDependency(ea.Pass) {
+		return
+	}
+
+	pkg, cg, err := ea.buildSSA()
+	if err != nil {
+		if len(ea.Pass.Files) > 0 {
+			ea.Pass.Reportf(ea.Pass.Files[0].Package,
+				"%s call graph failed, falling back to the syntax backend: %v", ea.CallGraphMode, err)
+		}
+		return
+	}
+
+	ea.ssaCallees = make(map[token.Pos]StringSet)
+	for fn, node := range cg.Nodes {
+		if fn == nil || fn.Pkg != pkg {
+			continue
+		}
+		for _, edge := range node.Out {
+			if edge.Site == nil {
+				continue
+			}
+			pos := edge.Site.Common().Pos()
+			if ea.ssaCallees[pos] == nil {
+				ea.ssaCallees[pos] = NewStringSet()
+			}
+			ea.ssaTargets(cg, edge.Callee.Func, ea.ssaCallees[pos], make(map[*ssa.Function]bool))
+		}
+	}
+}
+
+// buildSSA builds the package like the buildssa analyzer does, with its imports created
+// from type information only, without function bodies, and computes its call graph.
+// The SSA builder panics on code it does not support, which is reported as an error.
+func (ea *EffectAnalysis) buildSSA() (pkg *ssa.Package, cg *callgraph.Graph, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	prog := ssa.NewProgram(ea.Pass.Fset, ssa.InstantiateGenerics)
+	created := make(map[*types.Package]bool)
+	var createAll func(pkgs []*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if !created[p] {
+				created[p] = true
+				prog.CreatePackage(p, nil, nil, true)
+				createAll(p.Imports())
+			}
+		}
+	}
+	createAll(ea.Pass.Pkg.Imports())
+	pkg = prog.CreatePackage(ea.Pass.Pkg, ea.Pass.Files, ea.Pass.TypesInfo, false)
+	pkg.Build()
+
+	cg = cha.CallGraph(prog)
+	if ea.CallGraphMode == CallGraphVTA {
+		cg = vta.CallGraph(ssautil.AllFunctions(prog), cg)
+	}
+	return pkg, cg, nil
+}
+
+// inDependency reports whether the package of pass comes from the module cache or a
+// vendor directory rather than from the modules being analyzed
+func inDependency(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 {
+		return false
+	}
+	filename := pass.Fset.Position(pass.Files[0].Pos()).Filename
+	if strings.Contains(filepath.ToSlash(filename), "/vendor/") {
+		return true
+	}
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" && build.Default.GOPATH != "" {
+		modCache = filepath.Join(filepath.SplitList(build.Default.GOPATH)[0], "pkg", "mod")
+	}
+	return modCache != "" && strings.HasPrefix(filename, modCache+string(filepath.Separator))
+}
+
+// ssaTargets adds the functions of this analysis reached by calling fn, looking
+// through synthetic wrappers such as bound method closures and interface thunks
+func (ea *EffectAnalysis) ssaTargets(cg *callgraph.Graph, fn *ssa.Function, result StringSet, seen map[*ssa.Function]bool) {
+	if seen[fn] {
+		return
+	}
+	seen[fn] = true
+
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if name, ok := ea.ssaFuncName(fn); ok {
+		result.Add(name)
+		return
+	}
+	if fn.Synthetic == "" {
+		return
+	}
+	if node := cg.Nodes[fn]; node != nil {
+		for _, edge := range node.Out {
+			ea.ssaTargets(cg, edge.Callee.Func, result, seen)
+		}
+	}
+}
+
+// ssaFuncName returns the name of the function unit an SSA function was built from
+func (ea *EffectAnalysis) ssaFuncName(fn *ssa.Function) (string, bool) {
+	if lit, ok := fn.Syntax().(*ast.FuncLit); ok {
+		name, ok := ea.funcLits[lit]
+		return name, ok
+	}
+	if fn.Synthetic != "" {
+		return "", false
+	}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok {
+		return "", false
+	}
+	info, ok := ea.lookupFunction(obj)
+	if !ok {
+		return "", false
+	}
+	return info.Name, true
+}
+
+// dynamicTargets returns the functions a dynamic call in info may invoke according to
+// the SSA call graph: calls through interfaces and calls of function values. Contracts
+// stay authoritative, so it reports false for calls with a declared contract as well as
+// for static calls, with the syntactic backend, and for calls the SSA call graph found
+// no targets for. Those calls are resolved syntactically.
+func (ea *EffectAnalysis) dynamicTargets(info *FunctionInfo, call *ast.CallExpr) (StringSet, bool) {
+	if ea.ssaCallees == nil {
+		return nil, false
+	}
+	if callee := ea.calleeFunc(call); callee != nil {
+		recv := callee.Type().(*types.Signature).Recv()
+		if recv == nil || !types.IsInterface(recv.Type()) {
+			return nil, false
+		}
+		if _, ok := ea.typeParamRecv(call); ok {
+			// Type parameter methods are substituted at instantiations instead
+			return nil, false
+		}
+		if method, ok := ea.lookupFunction(callee); ok && method.HasDeclaration {
+			// Calls through interfaces with a contract get the declared effects
+			return nil, false
+		}
+	} else if ea.isEffectParamCall(info, call) {
+		// Effect parameters are instantiated at each call of info instead
+		return nil, false
+	} else if _, ok := ea.funcContract(ea.assignedObject(call.Fun), ea.Pass.TypesInfo.TypeOf(call.Fun)); ok {
+		// Calls of function values with a contract get the declared effects
+		return nil, false
+	}
+
+	targets := ea.ssaCallees[call.Lparen]
+	return targets, len(targets) > 0
+}
+
+// isEffectParamCall reports whether call calls an effect parameter of info
+func (ea *EffectAnalysis) isEffectParamCall(info *FunctionInfo, call *ast.CallExpr) bool {
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || info.Object == nil {
+		return false
+	}
+	params := info.Object.Type().(*types.Signature).Params()
+	for _, i := range info.EffectParams {
+		if i < params.Len() && ea.Pass.TypesInfo.Uses[ident] == params.At(i) {
+			return true
+		}
+	}
+	return false
+}
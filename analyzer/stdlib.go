@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	_ "embed"
+	"go/build"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// stdlibEffects is the built-in catalog of the effects of standard library functions,
+// in the format of an effect registry
+//
+//go:embed stdlib-effects.json
+var stdlibEffects []byte
+
+// StdlibCatalog returns the built-in catalog of the effects of standard library functions,
+// such as network for net/http.(*Client).Do and io[fs] for os.WriteFile
+func StdlibCatalog() (*EffectDeclarations, error) {
+	return parseEffectDeclarations(stdlibEffects)
+}
+
+// StdlibCatalogJSON returns the built-in catalog as JSON, to be copied into an effect
+// registry and edited
+func StdlibCatalogJSON() []byte {
+	return stdlibEffects
+}
+
+// withStdlibCatalog adds the entries of the built-in catalog to the JSON declarations.
+// Declarations already in effects take priority, so the effect registry overrides the catalog.
+func withStdlibCatalog(effects ParsedEffects) (ParsedEffects, error) {
+	catalog, err := StdlibCatalog()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := catalog.ParseAll()
+	if err != nil {
+		return nil, err
+	}
+	for name, expr := range effects {
+		parsed[name] = expr
+	}
+	return parsed, nil
+}
+
+// inStandardLibrary reports whether the package of pass is in the standard library.
+// The catalog describes standard library functions to their callers, so it is not
+// applied to their bodies: effects found in there would hide the catalog's in Facts.
+func inStandardLibrary(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 || build.Default.GOROOT == "" {
+		return false
+	}
+	filename := pass.Fset.Position(pass.Files[0].Pos()).Filename
+	return strings.HasPrefix(filename, filepath.Join(build.Default.GOROOT, "src")+string(filepath.Separator))
+}
@@ -0,0 +1,263 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// Strict modes selected with the -strict flag, for calls whose callee's effects are unknown
+const (
+	// StrictOff treats unknown callees as effect-free
+	StrictOff = ""
+	// StrictReport reports calls of unknown callees in functions with a declaration
+	StrictReport = "report"
+	// StrictTop treats unknown callees as having any effect
+	StrictTop = "top"
+	// StrictRegistry requires an effect registry entry for unknown functions of other packages
+	StrictRegistry = "registry"
+)
+
+// TopEffect is the effect of unknown callees in the top strict mode.
+// No declaration covers it, so it is reported wherever it propagates to.
+const TopEffect = "*"
+
+// UnknownCall is a call whose callee's effects are not known from a declaration,
+// from Facts or from an effect registry
+type UnknownCall struct {
+	Callee   string // Callee name, or the called expression when it could not be resolved
+	Position token.Pos
+	Kind     CallKind
+	Dynamic  bool // Call through an interface or a function value without known targets
+	// Unresolved means Callee is the called expression, such as hook or fs[0]
+	Unresolved bool
+}
+
+// validStrictMode reports whether mode is one of the strict modes
+func validStrictMode(mode string) bool {
+	switch mode {
+	case StrictOff, StrictReport, StrictTop, StrictRegistry:
+		return true
+	}
+	return false
+}
+
+// CollectUnknownCalls records the calls of every function unit whose callee's effects
+// are unknown. In the top strict mode they get a call site to a callee with any effect.
+func (ea *EffectAnalysis) CollectUnknownCalls() {
+	if ea.StrictMode == StrictOff {
+		return
+	}
+
+	for _, info := range ea.Functions {
+		ea.inspectCalls(info, func(call *ast.CallExpr, kind CallKind) {
+			unknown, ok := ea.unknownCall(info, call)
+			if !ok {
+				return
+			}
+			unknown.Kind = kind
+			info.UnknownCalls = append(info.UnknownCalls, unknown)
+		})
+	}
+
+	if ea.StrictMode != StrictTop {
+		return
+	}
+	// Callees with any effect are added once every call has been classified. Callees
+	// whose effects are only known in part, such as functions of other packages without
+	// a declaration, keep them and get any effect at these calls.
+	// Callees whose Facts carry any effect already need no other call site.
+	withTop := NewStringSet()
+	for name, info := range ea.Functions {
+		if info.ComputedEffects.Contains(TopEffect) {
+			withTop.Add(name)
+		}
+	}
+	for _, info := range ea.unitsWithUnknownCalls() {
+		for _, call := range info.UnknownCalls {
+			if withTop.Contains(call.Callee) {
+				continue
+			}
+			ea.addCallSite(info, CallSite{
+				Callee:   ea.topFunction(call.Callee).Name,
+				Position: call.Position,
+				Kind:     call.Kind,
+				Inferred: NewStringSet(TopEffect),
+			})
+		}
+	}
+}
+
+// unitsWithUnknownCalls returns the function units with unknown calls
+func (ea *EffectAnalysis) unitsWithUnknownCalls() []*FunctionInfo {
+	var result []*FunctionInfo
+	for _, info := range ea.Functions {
+		if len(info.UnknownCalls) > 0 {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
+// unknownCall returns the call at call as an unknown call if its callee's effects are unknown
+func (ea *EffectAnalysis) unknownCall(info *FunctionInfo, call *ast.CallExpr) (UnknownCall, bool) {
+	callee := ea.calleeFunc(call)
+	if callee == nil {
+		if tv, ok := ea.Pass.TypesInfo.Types[call.Fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+			// Conversions and builtins have no effects
+			return UnknownCall{}, false
+		}
+		if _, ok := ea.dynamicTargets(info, call); ok {
+			return UnknownCall{}, false
+		}
+		if _, ok := ea.funcContract(ea.assignedObject(call.Fun), ea.Pass.TypesInfo.TypeOf(call.Fun)); ok {
+			return UnknownCall{}, false
+		}
+		if ea.isEffectParamCall(info, call) || len(ea.funcValues(call.Fun)) > 0 {
+			return UnknownCall{}, false
+		}
+		return UnknownCall{Callee: types.ExprString(call.Fun), Position: call.Pos(), Dynamic: true, Unresolved: true}, true
+	}
+
+	if _, ok := ea.typeParamRecv(call); ok {
+		// Type parameter methods have the effects of the constraint or of the type argument
+		return UnknownCall{}, false
+	}
+	name := FuncKey(callee)
+	if _, ok := ea.Functions[name]; ok && callee.Pkg() == ea.Pass.Pkg {
+		return UnknownCall{}, false
+	}
+
+	recv := callee.Type().(*types.Signature).Recv()
+	dynamic := recv != nil && types.IsInterface(recv.Type())
+	if dynamic {
+		if _, ok := ea.dynamicTargets(info, call); ok {
+			return UnknownCall{}, false
+		}
+	}
+	if callee.Pkg() == ea.Pass.Pkg {
+		// Local functions are analyzed; only their interface methods can be unknown
+		return UnknownCall{Callee: name, Position: call.Pos(), Dynamic: true}, dynamic
+	}
+	if callee.Pkg() != nil && ea.knownImport(call, callee) {
+		return UnknownCall{}, false
+	}
+	return UnknownCall{Callee: name, Position: call.Pos(), Dynamic: dynamic}, true
+}
+
+// knownImport reports whether the effects of a call of a function of another package
+// are known: inferred from the call's arguments, declared in the effect registry or in
+// the stdlib catalog, or carried by Facts that are fully known. Facts of functions
+// without a declaration are fully known when no call in their bodies is unknown.
+func (ea *EffectAnalysis) knownImport(call *ast.CallExpr, callee *types.Func) bool {
+	if _, ok := ea.inferEffects(call); ok {
+		return true
+	}
+	if _, ok := ea.Resolver.ResolveJSONExpr(FuncKey(callee), callee.Pkg().Path()); ok {
+		return true
+	}
+	var fact FunctionEffectsFact
+	return !ea.DisableFacts && ea.Pass.ImportObjectFact(callee.Origin(), &fact) && (fact.Declared || fact.Known)
+}
+
+// partlyUnknown returns the names of the undeclared function units whose effects are
+// not fully known: the ones with unknown calls or without a body, and the ones calling
+// them, transitively. Declarations stop the propagation.
+func (ea *EffectAnalysis) partlyUnknown() StringSet {
+	result := NewStringSet()
+	var worklist []string
+	for name, info := range ea.Functions {
+		if info.HasDeclaration {
+			continue
+		}
+		if len(info.UnknownCalls) > 0 || (info.Decl != nil && info.Decl.Body == nil) {
+			result.Add(name)
+			worklist = append(worklist, name)
+		}
+	}
+	for len(worklist) > 0 {
+		name := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		for _, caller := range ea.CallGraph.CalledBy[name] {
+			info, ok := ea.Functions[caller]
+			if !ok || info.HasDeclaration || result.Contains(caller) {
+				continue
+			}
+			result.Add(caller)
+			worklist = append(worklist, caller)
+		}
+	}
+	return result
+}
+
+// topFunction returns the synthetic function info of an unknown callee with any effect
+func (ea *EffectAnalysis) topFunction(name string) *FunctionInfo {
+	if info, ok := ea.Functions[name]; ok {
+		return info
+	}
+	top := NewStringSetFromSlice([]string{TopEffect})
+	info := &FunctionInfo{
+		Name:            name,
+		DeclaredEffects: top,
+		ComputedEffects: top.Clone(),
+		HasDeclaration:  true,
+		CallSites:       []CallSite{},
+	}
+	ea.Functions[name] = info
+	return info
+}
+
+// CheckUnknownCalls reports the unknown calls of functions with a declaration, in the
+// report and registry strict modes. Packages with unknown calls in functions with a
+// declaration also get a summary of their unknown callees, so that packages without
+// declarations do not fail while strict mode is adopted gradually.
+func (ea *EffectAnalysis) CheckUnknownCalls() {
+	if ea.StrictMode == StrictOff {
+		return
+	}
+
+	callees := NewStringSet()
+	annotated := false
+	for _, fn := range ea.Functions {
+		for _, call := range fn.UnknownCalls {
+			callees.Add(ea.unknownCalleeName(call))
+			if !fn.HasDeclaration {
+				continue
+			}
+			annotated = true
+
+			switch {
+			case ea.StrictMode == StrictReport && call.Dynamic:
+				ea.Pass.Reportf(call.Position, "function calls %s whose callees are unknown", ea.displayName(call.Callee))
+			case ea.StrictMode == StrictReport:
+				ea.Pass.Reportf(call.Position, "function calls %s whose effects are unknown", ea.displayName(call.Callee))
+			case ea.StrictMode == StrictRegistry && !call.Dynamic:
+				ea.Pass.Reportf(call.Position, "function calls %s which has no entry in the effect registry", ea.displayName(call.Callee))
+			}
+		}
+	}
+
+	if len(callees) == 0 || len(ea.Pass.Files) == 0 {
+		return
+	}
+	names := callees.ToSlice()
+	debugLog("Unknown callees in %s: %v", ea.Pass.Pkg.Path(), names)
+	if !annotated {
+		return
+	}
+	ea.Pass.Reportf(ea.Pass.Files[0].Name.Pos(),
+		"package calls %d callees with unknown effects: %s", len(names), strings.Join(names, ", "))
+}
+
+// unknownCalleeName returns the name of an unknown callee in the summary: its name, or
+// the called expression and its position for calls of unresolved function values
+func (ea *EffectAnalysis) unknownCalleeName(call UnknownCall) string {
+	if !call.Unresolved {
+		return ea.displayName(call.Callee)
+	}
+	pos := ea.Pass.Fset.Position(call.Position)
+	return fmt.Sprintf("%s at %s:%d:%d", call.Callee, filepath.Base(pos.Filename), pos.Line, pos.Column)
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 	"slices"
 	"sort"
 )
@@ -14,14 +15,85 @@ type FunctionInfo struct {
 	DeclaredEffects StringSet // Effects declared via // dirty: comment
 	ComputedEffects StringSet // Actual effects including those from called functions
 	HasDeclaration  bool      // Whether function has // dirty: comment
+	Abstract        bool      // Interface method whose declaration is a contract for implementations
+	Object          *types.Func
 	Decl            *ast.FuncDecl
-	CallSites       []CallSite // Functions called by this function
+	Lit             *ast.FuncLit   // Set instead of Decl for function literals
+	Inits           []*ast.GenDecl // Package-level variable declarations run by the package initialization
+	CallSites       []CallSite     // Functions called by this function
+	EffectParams    []int          // Indices of function-typed parameters bound to effect variables
+
+	// Calls of methods on type parameters of a generic function, and the effects of an
+	// undeclared generic function apart from them, for substitution at instantiations
+	TypeParamCalls []TypeParamCall
+	BaseEffects    StringSet
+
+	// Calls whose callee's effects are unknown, recorded in strict mode
+	UnknownCalls []UnknownCall
+
+	// Effects of goroutines spawned by this function or its callees.
+	// They are kept apart from ComputedEffects when the declaration has an async clause.
+	HasAsyncDeclaration  bool
+	DeclaredAsyncEffects StringSet
+	AsyncEffects         StringSet
+
+	// Effects forbidden by //dirty-forbid: comments on the function, its receiver type
+	// or the package
+	Forbidden []*ForbiddenEffects
+}
+
+// Node returns the syntax of the function: its declaration or function literal
+func (f *FunctionInfo) Node() ast.Node {
+	if f.Decl != nil {
+		return f.Decl
+	}
+	if f.Lit != nil {
+		return f.Lit
+	}
+	return nil
+}
+
+// CallKind distinguishes how a function is called
+type CallKind int
+
+const (
+	// CallDirect is an ordinary call
+	CallDirect CallKind = iota
+	// CallGo is a call in a go statement, running in a new goroutine
+	CallGo
+	// CallDefer is a call in a defer statement
+	CallDefer
+)
+
+func (k CallKind) String() string {
+	switch k {
+	case CallGo:
+		return "go"
+	case CallDefer:
+		return "defer"
+	default:
+		return "call"
+	}
 }
 
 // CallSite represents a function call location
 type CallSite struct {
 	Callee   string
 	Position token.Pos
+	Kind     CallKind
+	// Instantiations lists the functions passed for the callee's effect parameters
+	// and the methods of type arguments substituted for calls on its type parameters
+	Instantiations []string
+	// Substituted means the callee's calls on type parameters are replaced by Instantiations
+	Substituted bool
+	// TypeParamMethod means the call is a method call on a type parameter
+	TypeParamMethod bool
+	// Promoted lists the embedded fields a called method is promoted through
+	Promoted []string
+	// Inferred holds the effects of the call in addition to the callee's: those
+	// inferred from its arguments, such as the tables of a SQL query, or any effect
+	// for calls of callees with unknown effects in the top strict mode
+	Inferred StringSet
 }
 
 // CallGraph represents the function call relationships
@@ -42,15 +114,20 @@ func NewCallGraph() *CallGraph {
 
 // AddCall records that caller calls callee at position
 func (g *CallGraph) AddCall(caller, callee string, pos token.Pos) {
-	g.Calls[caller] = append(g.Calls[caller], CallSite{
+	g.AddCallSite(caller, CallSite{
 		Callee:   callee,
 		Position: pos,
 	})
+}
+
+// AddCallSite records a call site of caller
+func (g *CallGraph) AddCallSite(caller string, call CallSite) {
+	g.Calls[caller] = append(g.Calls[caller], call)
 
 	// Update reverse mapping
-	found := slices.Contains(g.CalledBy[callee], caller)
+	found := slices.Contains(g.CalledBy[call.Callee], caller)
 	if !found {
-		g.CalledBy[callee] = append(g.CalledBy[callee], caller)
+		g.CalledBy[call.Callee] = append(g.CalledBy[call.Callee], caller)
 	}
 }
 
@@ -137,6 +214,17 @@ func (s StringSet) Difference(other StringSet) StringSet {
 	return diff
 }
 
+// Intersection returns items in s that are also in other
+func (s StringSet) Intersection(other StringSet) StringSet {
+	result := make(StringSet)
+	for item := range s {
+		if other.Contains(item) {
+			result[item] = struct{}{}
+		}
+	}
+	return result
+}
+
 // Union returns a new set containing all items from both sets
 func (s StringSet) Union(other StringSet) StringSet {
 	result := s.Clone()
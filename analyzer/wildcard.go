@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Wildcard is the operation or target of an effect label matching any other:
+// select[*] covers every select, *[users] every operation on users
+const Wildcard = "*"
+
+// splitLabel returns the operation and the target of an effect label, an empty
+// target for labels without one
+func splitLabel(label string) (op, target string) {
+	op, rest, ok := strings.Cut(label, "[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return label, ""
+	}
+	return op, strings.TrimSuffix(rest, "]")
+}
+
+// isWildcardLabel reports whether an effect label has a wildcard operation or target
+func isWildcardLabel(label string) bool {
+	if label == TopEffect {
+		return false
+	}
+	op, target := splitLabel(label)
+	return op == Wildcard || target == Wildcard
+}
+
+// Wildcards returns the wildcard labels of s
+func (s StringSet) Wildcards() StringSet {
+	result := make(StringSet)
+	for label := range s {
+		if isWildcardLabel(label) {
+			result[label] = struct{}{}
+		}
+	}
+	return result
+}
+
+// wildcardNote explains the effects that the wildcard labels of declared do not
+// cover, for diagnostics. It is empty for declarations without wildcards.
+func wildcardNote(declared, missing StringSet) string {
+	wildcards := declared.Wildcards()
+	if len(wildcards) == 0 || len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (wildcards [%s] do not cover [%s])",
+		joinEffects(wildcards.ToSlice()), joinEffects(missing.ToSlice()))
+}
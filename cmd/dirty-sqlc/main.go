@@ -0,0 +1,66 @@
+// Package main implements dirty-sqlc, which generates an effect registry for the
+// Queries methods generated by sqlc from the tables and statements of their queries.
+// By default the registry is written into the directory of each generated package,
+// where the analyzer reads it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/naoyafurudono/dirty/sqlc"
+)
+
+func main() {
+	config := flag.String("config", "", "sqlc configuration file (default: sqlc.yaml, sqlc.yml or sqlc.json)")
+	output := flag.String("o", "", "effect registry to write all entries to (default: effect-registry.json in each generated package directory)")
+	flag.Parse()
+
+	if err := run(*config, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "dirty-sqlc:", err)
+		os.Exit(1)
+	}
+}
+
+func run(config, output string) error {
+	if config == "" {
+		var err error
+		if config, err = sqlc.FindConfig("."); err != nil {
+			return err
+		}
+	}
+
+	packages, err := sqlc.LoadConfig(config)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		// One registry per generated package, next to its Queries methods
+		for _, pkg := range packages {
+			effects, err := sqlc.GeneratePackage(pkg)
+			if err != nil {
+				return err
+			}
+			if err := write(sqlc.RegistryPath(pkg), effects); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	effects, err := sqlc.Generate(packages)
+	if err != nil {
+		return err
+	}
+	return write(output, effects)
+}
+
+// write writes effects to the effect registry at path and reports it
+func write(path string, effects map[string]string) error {
+	if err := sqlc.WriteRegistry(path, effects); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d query effects to %s\n", len(effects), path)
+	return nil
+}
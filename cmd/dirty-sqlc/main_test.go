@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModule writes a module with a sqlc configuration, its generated package
+// and a package calling it, and returns its directory
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"sqlc.yaml": `version: "2"
+sql:
+  - engine: postgresql
+    queries: queries
+    schema: schema.sql
+    gen:
+      go:
+        package: db
+        out: internal/db
+`,
+		"queries/users.sql": `-- name: GetUser :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1;
+`,
+		"internal/db/queries.sql.go": `package db
+
+type Queries struct{}
+
+func (q *Queries) GetUser(id int) error { return nil }
+
+func (q *Queries) DeleteUser(id int) error { return nil }
+`,
+		"handler/handler.go": `package handler
+
+import "example.com/app/internal/db"
+
+// dirty: { select[users] }
+func Show(q *db.Queries) error {
+	return q.GetUser(1)
+}
+
+// dirty: { select[users] }
+func Remove(q *db.Queries) error {
+	return q.DeleteUser(1)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRegistryIsReadByAnalyzer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the dirty binary")
+	}
+	bin := filepath.Join(t.TempDir(), "dirty")
+	if out, err := exec.Command("go", "build", "-o", bin, "../dirty").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	dir := writeModule(t)
+
+	// Run from the module root with the default output
+	t.Chdir(dir)
+	if err := run("", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "internal", "db", "effect-registry.json")); err != nil {
+		t.Fatalf("registry not written into the generated package: %v", err)
+	}
+
+	cmd := exec.Command(bin, "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected the analysis to fail:\n%s", out)
+	}
+	want := "function calls example.com/app/internal/db.(*Queries).DeleteUser which has effects [delete[users]] not declared in this function"
+	if !strings.Contains(string(out), want) {
+		t.Errorf("output does not contain %q:\n%s", want, out)
+	}
+	if strings.Contains(string(out), "GetUser") {
+		t.Errorf("unexpected diagnostic for GetUser:\n%s", out)
+	}
+}
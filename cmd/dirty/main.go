@@ -2,29 +2,61 @@
 package main
 
 import (
+	"flag"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/naoyafurudono/dirty/analyzer"
-	"golang.org/x/tools/go/analysis/multichecker"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
-	// Check if running in multi-package mode
-	if len(os.Args) > 1 && (containsPattern(os.Args[1:], "...") || len(os.Args) > 2) {
-		// Use multichecker for multiple packages
-		multichecker.Main(analyzer.Analyzer)
-	} else {
-		// Use singlechecker for single package
-		singlechecker.Main(analyzer.Analyzer)
+	// Print the built-in catalog of standard library effects. The flag is registered
+	// for the usage message, but handled before singlechecker requires packages.
+	flag.Bool("print-stdlib", false, "print the built-in catalog of standard library effects and exit")
+	if printStdlib(os.Args[1:]) {
+		_, _ = os.Stdout.Write(analyzer.StdlibCatalogJSON())
+		return
 	}
+
+	// singlechecker takes any number of packages, and the analyzer's flags unprefixed
+	singlechecker.Main(analyzer.Analyzer)
+}
+
+// checkerValueFlags lists the flags singlechecker adds that take a value
+var checkerValueFlags = map[string]bool{
+	"c": true, "debug": true, "cpuprofile": true, "memprofile": true, "trace": true,
 }
 
-func containsPattern(args []string, pattern string) bool {
-	for _, arg := range args {
-		if arg == pattern || (len(arg) > 3 && arg[len(arg)-3:] == pattern) {
-			return true
+// printStdlib reports whether args set the -print-stdlib flag before the first package.
+// The values of the analyzer's and singlechecker's flags are skipped.
+func printStdlib(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			return false
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "print-stdlib" {
+			if !hasValue {
+				return true
+			}
+			enabled, err := strconv.ParseBool(value)
+			return err == nil && enabled
+		}
+		if !hasValue && takesValue(name) {
+			i++
 		}
 	}
 	return false
 }
+
+// takesValue reports whether the flag called name takes a value as the next argument
+func takesValue(name string) bool {
+	if f := analyzer.Analyzer.Flags.Lookup(name); f != nil {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		return !ok || !b.IsBoolFlag()
+	}
+	return checkerValueFlags[name]
+}
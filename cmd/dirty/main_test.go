@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildDirty builds the dirty binary into a temporary directory
+func buildDirty(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "dirty")
+	out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// writeModule writes a module with a package calling an undeclared function of the
+// standard library from a declared function, and returns its directory
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"app.go": `package app
+
+import "time"
+
+// dirty: { }
+func Stamp() int64 {
+	return time.Now().Unix()
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// runDirty runs the binary in dir and returns its combined output
+func runDirty(t *testing.T, bin, dir string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func TestFlags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildDirty(t)
+	dir := writeModule(t)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"./..."}, ""},
+		{[]string{"-strict=report", "./..."}, "function calls time.Now whose effects are unknown"},
+		{[]string{"-stdlib", "./..."}, "function calls time.Now which has effects [nondeterminism] not declared in this function"},
+		{[]string{"-callgraph=vta", "-infer=sql", "-operations=", "-stdlib", "./..."}, "[nondeterminism]"},
+		{[]string{"-stdlib", "-strict=report", "."}, "[nondeterminism]"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			out, err := runDirty(t, bin, dir, tt.args...)
+			if strings.Contains(out, "flag provided but not defined") {
+				t.Fatalf("flag rejected:\n%s", out)
+			}
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected failure: %v\n%s", err, out)
+				}
+				return
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestInvalidFlags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildDirty(t)
+	dir := writeModule(t)
+
+	for _, tt := range []struct {
+		arg  string
+		want string
+	}{
+		{"-callgraph=bogus", `invalid call graph backend "bogus"`},
+		{"-strict=bogus", `invalid strict mode "bogus"`},
+		{"-operations=write", `invalid operation hierarchy "write"`},
+	} {
+		out, err := runDirty(t, bin, dir, tt.arg, "./...")
+		if err == nil || !strings.Contains(out, tt.want) {
+			t.Errorf("%s: error = %v, output does not contain %q:\n%s", tt.arg, err, tt.want, out)
+		}
+	}
+}
+
+func TestPrintStdlib(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildDirty(t)
+	dir := writeModule(t)
+
+	for _, args := range [][]string{
+		{"-print-stdlib"},
+		{"-callgraph", "vta", "-print-stdlib"},
+		{"-stdlib", "-strict", "report", "-print-stdlib=true"},
+	} {
+		out, err := runDirty(t, bin, dir, args...)
+		if err != nil {
+			t.Fatalf("%v: unexpected failure: %v\n%s", args, err, out)
+		}
+		if !strings.Contains(out, `"time.Now"`) {
+			t.Errorf("%v: catalog does not contain time.Now:\n%s", args, out)
+		}
+	}
+
+	// A package named stdlib is analyzed like any other
+	pkg := filepath.Join(dir, "stdlib")
+	if err := os.Mkdir(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package stdlib\n\nimport \"time\"\n\n// dirty: { }\nfunc Now() time.Time {\n\treturn time.Now()\n}\n"
+	if err := os.WriteFile(filepath.Join(pkg, "stdlib.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := runDirty(t, bin, pkg, "-stdlib", "./")
+	if want := "function calls time.Now which has effects [nondeterminism]"; !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q:\n%s", want, out)
+	}
+	out, _ = runDirty(t, bin, dir, "-stdlib", "stdlib")
+	if strings.Contains(out, `"time.Now"`) {
+		t.Errorf("the stdlib argument printed the catalog:\n%s", out)
+	}
+}
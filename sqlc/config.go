@@ -0,0 +1,138 @@
+package sqlc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFiles lists the names of sqlc configuration files, in the order sqlc looks for them
+var ConfigFiles = []string{"sqlc.yaml", "sqlc.yml", "sqlc.json"}
+
+// Package is a Go package generated by sqlc from a set of query files
+type Package struct {
+	// Name is the name of the generated package
+	Name string
+	// Out is the directory of the generated package
+	Out string
+	// Queries lists the query files and directories
+	Queries []string
+}
+
+// LoadConfig reads the Go packages generated by the sqlc configuration at path.
+// Both version 1 ("packages") and version 2 ("sql" with "gen.go") configurations
+// are supported. Paths are made relative to the working directory.
+func LoadConfig(path string) ([]Package, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by the user
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a mapping", path)
+	}
+
+	dir := filepath.Dir(path)
+	var packages []Package
+	switch version := stringField(root, "version"); version {
+	case "1":
+		for _, entry := range listField(root, "packages") {
+			pkg, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			packages = append(packages, Package{
+				Name:    stringField(pkg, "name"),
+				Out:     filepath.Join(dir, stringField(pkg, "path")),
+				Queries: joinPaths(dir, stringsField(pkg, "queries")),
+			})
+		}
+	case "2":
+		for _, entry := range listField(root, "sql") {
+			sql, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			gen, _ := sql["gen"].(map[string]any)
+			goGen, ok := gen["go"].(map[string]any)
+			if !ok {
+				// Only Go code has Queries methods
+				continue
+			}
+			packages = append(packages, Package{
+				Name:    stringField(goGen, "package"),
+				Out:     filepath.Join(dir, stringField(goGen, "out")),
+				Queries: joinPaths(dir, stringsField(sql, "queries")),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported version %q", path, version)
+	}
+	return packages, nil
+}
+
+// FindConfig returns the sqlc configuration file in dir
+func FindConfig(dir string) (string, error) {
+	for _, name := range ConfigFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no sqlc configuration in %s", dir)
+}
+
+// stringField returns a scalar field of a mapping
+func stringField(m map[string]any, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64, int:
+		// Versions written as numbers
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// listField returns a sequence field of a mapping
+func listField(m map[string]any, key string) []any {
+	list, _ := m[key].([]any)
+	return list
+}
+
+// stringsField returns a field of a mapping that is a string or a list of strings
+func stringsField(m map[string]any, key string) []string {
+	if s := stringField(m, key); s != "" {
+		return []string{s}
+	}
+	var result []string
+	for _, item := range listField(m, key) {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// joinPaths makes paths relative to the configuration directory
+func joinPaths(dir string, paths []string) []string {
+	result := make([]string, len(paths))
+	for i, path := range paths {
+		result[i] = filepath.Join(dir, path)
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+// Package sqlc generates effect registries for the data layer generated by sqlc.
+// The effects of each query are inferred from its SQL and declared for the
+// method sqlc generates for it on the Queries type.
+package sqlc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/naoyafurudono/dirty/analyzer"
+	"golang.org/x/mod/modfile"
+)
+
+// Query is a named query of a sqlc query file
+type Query struct {
+	// Name is the name of the query and of its generated method
+	Name string
+	// Command is the kind of the query, such as ":one" or ":exec"
+	Command string
+	// SQL is the text of the query
+	SQL string
+}
+
+// queryName matches the comments naming queries: "-- name: GetUser :one"
+// and "/* name: GetUser :one */"
+var queryName = regexp.MustCompile(`^\s*(?:--|/\*)\s*name:\s*(\w+)\s+(:\w+)`)
+
+// ParseQueries parses the named queries of a query file
+func ParseQueries(src string) []Query {
+	var queries []Query
+	var sql strings.Builder
+	flush := func() {
+		if len(queries) > 0 {
+			queries[len(queries)-1].SQL = strings.TrimSpace(sql.String())
+		}
+		sql.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := queryName.FindStringSubmatch(line); m != nil {
+			flush()
+			queries = append(queries, Query{Name: m[1], Command: m[2]})
+			continue
+		}
+		sql.WriteString(line)
+		sql.WriteByte('\n')
+	}
+	flush()
+	return queries
+}
+
+// LoadQueries reads the named queries of the query files and directories of pkg
+func LoadQueries(pkg Package) ([]Query, error) {
+	var queries []Query
+	for _, path := range pkg.Queries {
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if info.IsDir() {
+			if files, err = filepath.Glob(filepath.Join(path, "*.sql")); err != nil {
+				return nil, err
+			}
+			sort.Strings(files)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file) // #nosec G304 - listed in the sqlc configuration
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, ParseQueries(string(data))...)
+		}
+	}
+	return queries, nil
+}
+
+// Generate returns the effect declarations of the Queries methods generated for the
+// packages. Methods are keyed by their qualified names, "example.com/db.(*Queries).GetUser",
+// when the import path of the generated package is known from its go.mod, and by
+// "(*Queries).GetUser" otherwise.
+func Generate(packages []Package) (map[string]string, error) {
+	effects := make(map[string]string)
+	for _, pkg := range packages {
+		generated, err := GeneratePackage(pkg)
+		if err != nil {
+			return nil, err
+		}
+		for name, effect := range generated {
+			effects[name] = effect
+		}
+	}
+	return effects, nil
+}
+
+// GeneratePackage returns the effect declarations of the Queries methods generated
+// for pkg, keyed like the ones of Generate
+func GeneratePackage(pkg Package) (map[string]string, error) {
+	queries, err := LoadQueries(pkg)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if importPath, ok := ImportPath(pkg.Out); ok {
+		prefix = importPath + "."
+	}
+	effects := make(map[string]string)
+	for _, query := range queries {
+		effects[prefix+"(*Queries)."+query.Name] = FormatEffects(analyzer.SQLEffects(query.SQL))
+	}
+	return effects, nil
+}
+
+// RegistryPath returns the effect registry of the generated package pkg. The analyzer
+// reads it when analyzing the package, and exports the effects of the Queries methods
+// as facts to the packages calling them.
+func RegistryPath(pkg Package) string {
+	return filepath.Join(pkg.Out, "effect-registry.json")
+}
+
+// FormatEffects formats effects as an effect declaration: "{ select[users] | insert[logs] }"
+func FormatEffects(effects analyzer.StringSet) string {
+	if len(effects) == 0 {
+		return "{ }"
+	}
+	return "{ " + strings.Join(effects.ToSlice(), " | ") + " }"
+}
+
+// ImportPath returns the import path of the package in dir, from the module path
+// in the nearest go.mod
+func ImportPath(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod")) // #nosec G304 - go.mod above the output directory
+		if err == nil {
+			module := modfile.ModulePath(data)
+			if module == "" {
+				return "", false
+			}
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", false
+			}
+			if rel == "." {
+				return module, true
+			}
+			return module + "/" + filepath.ToSlash(rel), true
+		}
+		if filepath.Dir(root) == root {
+			return "", false
+		}
+	}
+}
+
+// WriteRegistry writes effects to the effect registry at path. Entries already in
+// the registry are kept unless they are generated again.
+func WriteRegistry(path string, effects map[string]string) error {
+	decls := &analyzer.EffectDeclarations{Version: "1.0", Effects: make(map[string]string)}
+	if _, err := os.Stat(path); err == nil {
+		existing, err := analyzer.LoadEffectDeclarations(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if existing.Effects != nil {
+			decls.Effects = existing.Effects
+		}
+	}
+	for name, effect := range effects {
+		decls.Effects[name] = effect
+	}
+	return analyzer.WriteEffectDeclarations(path, decls)
+}
@@ -0,0 +1,113 @@
+package sqlc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/naoyafurudono/dirty/analyzer"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   map[string]string
+	}{
+		{
+			name:   "version 2 yaml",
+			config: "testdata/app/sqlc.yaml",
+			want: map[string]string{
+				"example.com/app/internal/db.(*Queries).GetUser":           "{ select[users] }",
+				"example.com/app/internal/db.(*Queries).ListUsersWithOrgs": "{ select[orgs] | select[users] }",
+				"example.com/app/internal/db.(*Queries).CreateUser":        "{ insert[users] }",
+				"example.com/app/internal/db.(*Queries).DeleteSessions":    "{ delete[sessions] }",
+				"example.com/app/internal/db.(*Queries).Ping":              "{ }",
+			},
+		},
+		{
+			name:   "version 1 json",
+			config: "testdata/legacy/sqlc.json",
+			want: map[string]string{
+				"github.com/naoyafurudono/dirty/sqlc/testdata/legacy/store.(*Queries).RenameAccount": "{ update[accounts] }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packages, err := LoadConfig(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Generate(packages)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	// Anchors, flow collections, block scalars and unquoted versions
+	src := `version: 2
+x-go: &go
+  package: db
+  out: internal/db
+sql:
+  - engine: postgresql
+    queries: [queries/users.sql]
+    gen: {go: *go}
+  - engine: postgresql
+    queries: >-
+      queries/
+    gen:
+      go:
+        <<: *go
+        out: internal/admin
+`
+	path := filepath.Join(t.TempDir(), "sqlc.yaml")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Dir(path)
+	want := []Package{
+		{Name: "db", Out: filepath.Join(dir, "internal/db"), Queries: []string{filepath.Join(dir, "queries/users.sql")}},
+		{Name: "db", Out: filepath.Join(dir, "internal/admin"), Queries: []string{filepath.Join(dir, "queries")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadConfig() = %#v, want %#v", got, want)
+	}
+}
+
+func TestWriteRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "effect-registry.json")
+	existing := `{"version": "1.0", "effects": {"Notify": "{ network[mail] }", "(*Queries).GetUser": "{ }"}}`
+	if err := os.WriteFile(path, []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteRegistry(path, map[string]string{"(*Queries).GetUser": "{ select[users] }"}); err != nil {
+		t.Fatal(err)
+	}
+	decls, err := analyzer.LoadEffectDeclarations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Notify":             "{ network[mail] }",
+		"(*Queries).GetUser": "{ select[users] }",
+	}
+	if !reflect.DeepEqual(decls.Effects, want) {
+		t.Errorf("registry = %v, want %v", decls.Effects, want)
+	}
+}
@@ -0,0 +1,5 @@
+-- name: DeleteSessions :execrows
+DELETE FROM sessions WHERE user_id = @user_id;
+
+/* name: Ping :exec */
+SELECT 1;
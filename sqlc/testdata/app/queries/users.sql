@@ -0,0 +1,13 @@
+-- name: GetUser :one
+SELECT id, name FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUsersWithOrgs :many
+SELECT users.id, orgs.name
+FROM users
+JOIN orgs ON orgs.id = users.org_id
+ORDER BY users.name;
+
+-- name: CreateUser :one
+INSERT INTO users (name) VALUES (sqlc.arg(name))
+RETURNING *;
@@ -0,0 +1,2 @@
+-- name: RenameAccount :exec
+UPDATE accounts SET name = ? WHERE id = ?;
@@ -0,0 +1,76 @@
+package async
+
+// Test case: effects of goroutines declared in async clauses
+
+// dirty: { insert[users] }
+func insertUser() {}
+
+// dirty: { network[mailer] }
+func sendMail() {}
+
+// dirty: { insert[audit_logs] }
+func audit() {}
+
+// Valid: the goroutine's effects are declared in the async clause
+// dirty: { insert[users] } async { network[mailer] }
+func Signup() {
+	insertUser()
+	go sendMail()
+}
+
+// Invalid: the goroutine's effects are not declared in the async clause
+// dirty: { insert[users] } async { }
+func SignupBroken() {
+	insertUser()
+	go sendMail() // want `goroutine runs sendMail which has effects \[network\[mailer\]\] not declared in the async effects of this function`
+}
+
+// Invalid: a goroutine's effects do not count as synchronous effects
+// dirty: { network[mailer] } async { }
+func SignupAsyncOnly() {
+	go sendMail() // want `goroutine runs sendMail which has effects \[network\[mailer\]\] not declared in the async effects of this function`
+}
+
+// Invalid: function literals run as goroutines too
+// dirty: { } async { network[mailer] }
+func Notify() {
+	go func() { // want `goroutine runs Notify\$1 which has effects \[insert\[audit_logs\], network\[mailer\]\] not declared in the async effects of this function`
+		sendMail()
+		audit()
+	}()
+}
+
+// Deferred calls run in the same goroutine, so their effects are synchronous
+// dirty: { insert[audit_logs] } async { }
+func Deferred() {
+	defer audit()
+}
+
+// Async effects of callees are async effects of their callers
+// dirty: { insert[users] } async { network[mailer] }
+func SignupTwice() {
+	Signup()
+	Signup()
+}
+
+// dirty: { insert[users] } async { }
+func SignupTwiceBroken() {
+	Signup() // want `function calls Signup which has async effects \[network\[mailer\]\] not declared in this function`
+}
+
+// Without an async clause, goroutine effects are checked against the declaration
+// dirty: { insert[users] }
+func SignupWithoutAsync() {
+	Signup()   // want `function calls Signup which has effects \[insert\[users\], network\[mailer\]\] not declared in this function`
+	go audit() // want `function calls audit which has effects \[insert\[audit_logs\]\] not declared in this function`
+}
+
+// Undeclared functions keep the effects of their goroutines apart as well
+func spawnMail() {
+	go sendMail()
+}
+
+// dirty: { } async { network[mailer] }
+func SpawnThroughHelper() {
+	spawnMail()
+}
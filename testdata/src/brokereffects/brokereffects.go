@@ -0,0 +1,81 @@
+package brokereffects
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// Test case: publish and consume effects inferred from message broker clients
+
+const (
+	ordersTopic   = "orders"
+	paymentsTopic = "payments"
+)
+
+// OrderService publishes through a writer bound to a topic
+type OrderService struct {
+	writer *kafka.Writer
+}
+
+func NewOrderService() *OrderService {
+	return &OrderService{writer: &kafka.Writer{Addr: "localhost:9092", Topic: ordersTopic}}
+}
+
+// Valid: the writer's topic
+// dirty: { publish[orders] }
+func (s *OrderService) Place(ctx context.Context, order []byte) error {
+	return s.writer.WriteMessages(ctx, kafka.Message{Value: order})
+}
+
+// Invalid: messages name their topics for writers without one
+// dirty: { publish[orders] }
+func Notify(ctx context.Context) error {
+	return (&kafka.Writer{}).WriteMessages(ctx, // want `function calls github\.com/segmentio/kafka-go\.\(\*Writer\)\.WriteMessages which has effects \[publish\[orders\], publish\[payments\]\] not declared in this function`
+		kafka.Message{Topic: ordersTopic},
+		kafka.Message{Topic: paymentsTopic},
+	)
+}
+
+// Invalid: readers consume their topics
+// dirty: { }
+func Listen() *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{GroupID: "billing", GroupTopics: []string{"orders", "refunds"}}) // want `function calls github\.com/segmentio/kafka-go\.NewReader which has effects \[consume\[orders\], consume\[refunds\]\] not declared in this function`
+}
+
+// Invalid: sarama producers and consumers
+// dirty: { publish[payments] }
+func Settle(ctx context.Context, producer sarama.SyncProducer, group sarama.ConsumerGroup, handler sarama.ConsumerGroupHandler) error {
+	if _, _, err := producer.SendMessage(&sarama.ProducerMessage{Topic: paymentsTopic, Value: sarama.StringEncoder("ok")}); err != nil {
+		return err
+	}
+	if err := producer.SendMessages([]*sarama.ProducerMessage{{Topic: "refunds"}}); err != nil { // want `function calls github\.com/IBM/sarama\.SyncProducer\.SendMessages which has effects \[publish\[refunds\]\] not declared in this function`
+		return err
+	}
+	return group.Consume(ctx, []string{ordersTopic}, handler) // want `function calls github\.com/IBM/sarama\.ConsumerGroup\.Consume which has effects \[consume\[orders\]\] not declared in this function`
+}
+
+// Invalid: NATS subjects
+// dirty: { publish[orders.created] }
+func Relay(nc *nats.Conn, data []byte) error {
+	if err := nc.Publish("orders.created", data); err != nil {
+		return err
+	}
+	if err := nc.PublishMsg(&nats.Msg{Subject: "orders.audit", Data: data}); err != nil { // want `function calls github\.com/nats-io/nats\.go\.\(\*Conn\)\.PublishMsg which has effects \[publish\[orders\.audit\]\] not declared in this function`
+		return err
+	}
+	_, err := nc.QueueSubscribe("orders.created", "relay", func(msg *nats.Msg) {}) // want `function calls github\.com/nats-io/nats\.go\.\(\*Conn\)\.QueueSubscribe which has effects \[consume\[orders\.created\]\] not declared in this function`
+	return err
+}
+
+// Valid: topics that are not constants and wildcard subjects are not inferred
+// dirty: { }
+func Forward(nc *nats.Conn, subject string, data []byte) error {
+	if err := nc.Publish(subject, data); err != nil {
+		return err
+	}
+	_, err := nc.Subscribe("orders.*", func(msg *nats.Msg) {})
+	return err
+}
@@ -0,0 +1,59 @@
+package chacallgraph
+
+// Test case: dynamic calls resolved by the CHA call graph backend
+
+type Notifier interface {
+	// Methods without a contract are resolved to every implementation in the program
+	Notify(msg string)
+}
+
+type mailer struct{}
+
+// dirty: { insert[mails] }
+func (mailer) Notify(msg string) {}
+
+type pager struct{}
+
+// dirty: { insert[pages] }
+func (pager) Notify(msg string) {}
+
+// Invalid: CHA does not follow values, so the pager is a target too
+// dirty: { insert[mails] }
+func SendMail() {
+	var n Notifier = mailer{}
+	n.Notify("hello") // want `function calls pager\.Notify which has effects \[insert\[pages\]\] not declared in this function`
+}
+
+// dirty: { insert[pages] }
+func Page() {
+	var n Notifier = pager{}
+	n.Notify("hello") // want `function calls mailer\.Notify which has effects \[insert\[mails\]\] not declared in this function`
+}
+
+// Valid: both implementations are declared
+// dirty: { insert[mails] | insert[pages] }
+func Broadcast(n Notifier) {
+	n.Notify("hello")
+}
+
+// dirty: { delete[users] }
+func deleteUsers(id int) {}
+
+// dirty: { delete[sessions] }
+func deleteSessions(id int) {}
+
+// Invalid: function values are resolved to every function of their signature whose
+// address is taken
+// dirty: { delete[users] }
+func Cleanup() {
+	for _, step := range []func(id int){deleteUsers} {
+		step(1) // want `function calls deleteSessions which has effects \[delete\[sessions\]\] not declared in this function`
+	}
+}
+
+// dirty: { delete[sessions] }
+func Logout() {
+	for _, step := range []func(id int){deleteSessions} {
+		step(1) // want `function calls deleteUsers which has effects \[delete\[users\]\] not declared in this function`
+	}
+}
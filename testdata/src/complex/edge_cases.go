@@ -21,12 +21,19 @@ func NoEffectComment() error {
 }
 
 // Malformed effect syntax (should be reported as error)
-// dirty: { select(user) }
+// dirty: { select(user) } // want `invalid effect declaration: expected '\|' or '}' at position 8, got \(`
 func MalformedEffect() error {
 	// Should report syntax error for using () instead of []
 	return nil
 }
 
+// Trailing text makes the declaration malformed: it is skipped rather than
+// declaring the function pure
+// dirty: { } select[user] // want `invalid effect declaration: unexpected token`
+func TrailingText() error {
+	return ManageUser(1, "select") // No error - function has no valid declaration
+}
+
 // Effect with special characters in target
 // dirty: { select[user_profile] | update[user-settings] | insert[user.preferences] }
 func SpecialCharacterTargets() error {
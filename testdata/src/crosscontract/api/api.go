@@ -0,0 +1,15 @@
+package api
+
+// Clock gives the current time of tests and production code
+type Clock interface {
+	// dirty: { }
+	Now() int64
+}
+
+// dirty: { }
+type Handler func()
+
+type Server struct {
+	// dirty: { }
+	OnStart func()
+}
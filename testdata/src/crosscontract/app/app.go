@@ -0,0 +1,27 @@
+package app // want package:"PackageEffectsFact\\{2 functions\\}"
+
+import "crosscontract/api"
+
+// Test case: empty contracts of other packages are checked like other contracts
+
+type clock struct{}
+
+// dirty: { nondeterminism }
+func (clock) Now() int64 { return 0 } // want Now:"FunctionEffectsFact\\[nondeterminism\\]"
+
+var _ api.Clock = clock{} // want `method clock\.Now has effects \[nondeterminism\] not declared in the contract of crosscontract/api\.Clock\.Now`
+
+// dirty: { network }
+func ping() {} // want ping:"FunctionEffectsFact\\[network\\]"
+
+// dirty: { }
+func noop() {} // want noop:"FunctionEffectsFact\\[\\]"
+
+var handler api.Handler = ping // want `function ping has effects \[network\] not declared in the contract of crosscontract/api\.Handler`
+
+var server = api.Server{
+	OnStart: ping, // want `function ping has effects \[network\] not declared in the contract of crosscontract/api\.Server\.OnStart`
+}
+
+// Valid: functions without effects satisfy empty contracts
+var quiet api.Handler = noop
@@ -0,0 +1,21 @@
+package alias // want package:"PackageEffectsFact\\{2 functions\\}"
+
+import defs "crossdefine/db"
+
+// Test case: named effect sets are qualified with the name the package is imported as
+
+// Valid: the set is found through the import name
+// dirty: { defs.UserOps }
+func Rename() { // want Rename:"FunctionEffectsFact\\[select\\[users\\] update\\[users\\]\\]"
+	defs.UpdateUser()
+}
+
+// Invalid: the set lacks the update
+// dirty: { defs.UserOps \ { update[users] } }
+func Load() { // want Load:"FunctionEffectsFact\\[select\\[users\\] update\\[users\\]\\]"
+	defs.UpdateUser() // want `function calls crossdefine/db\.UpdateUser which has effects \[select\[users\], update\[users\]\] not declared in this function`
+}
+
+// The package name is not in scope when the package is imported under another name
+// dirty: { db.UserOps } // want `undefined effect set db.UserOps`
+func Unqualified() {} // want Unqualified:"FunctionEffectsFact\\[\\]"
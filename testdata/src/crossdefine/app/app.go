@@ -0,0 +1,27 @@
+package app // want package:"PackageEffectsFact\\{3 functions\\}" package:"EffectDefinitionsFact\\{1 definitions\\}"
+
+import "crossdefine/db"
+
+// Test case: named effect sets of other packages are qualified with the package name
+
+//dirty-define: Ops = { db.UserOps | insert[audit_logs] }
+
+// Valid: the imported set covers the callee
+// dirty: { db.UserOps }
+func Rename() { // want Rename:"FunctionEffectsFact\\[select\\[users\\] update\\[users\\]\\]"
+	db.UpdateUser()
+}
+
+// Invalid: sets of this package building on imported ones
+// dirty: { db.AdminOps }
+func Audited() { // want Audited:"FunctionEffectsFact\\[delete\\[users\\] insert\\[audit_logs\\] select\\[users\\] update\\[users\\]\\]"
+	Rename()
+	Log() // want `function calls Log which has effects \[insert\[audit_logs\], select\[users\], update\[users\]\] not declared in this function`
+}
+
+// dirty: { Ops }
+func Log() {} // want Log:"FunctionEffectsFact\\[insert\\[audit_logs\\] select\\[users\\] update\\[users\\]\\]"
+
+// Unexported and unknown sets of other packages are undefined
+// dirty: { db.internalOps | db.Missing } // want `undefined effect set db.internalOps`
+func Hidden() {} // want Hidden:"FunctionEffectsFact\\[\\]"
@@ -0,0 +1,10 @@
+package db
+
+// Test case: named effect sets exported to other packages
+
+//dirty-define: UserOps = { select[users] | update[users] }
+//dirty-define: AdminOps = { UserOps | delete[users] }
+//dirty-define: internalOps = { select[migrations] }
+
+// dirty: { UserOps }
+func UpdateUser() {}
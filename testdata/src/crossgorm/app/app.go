@@ -0,0 +1,16 @@
+package app // want package:"PackageEffectsFact\\{1 functions\\}"
+
+import (
+	"crossgorm/models"
+
+	"gorm.io/gorm"
+)
+
+// Test case: table names of models of other packages come from facts
+
+// dirty: { }
+func Invoices(db *gorm.DB) []models.Invoice { // want Invoices:"FunctionEffectsFact\\[select\\[billing_invoices\\]\\]"
+	var invoices []models.Invoice
+	db.Find(&invoices) // want `function calls gorm\.io/gorm\.\(\*DB\)\.Find which has effects \[select\[billing_invoices\]\] not declared in this function`
+	return invoices
+}
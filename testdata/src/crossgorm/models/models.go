@@ -0,0 +1,11 @@
+package models
+
+// Invoice is stored in the table named by TableName
+type Invoice struct {
+	ID int64
+}
+
+// TableName is exported to other packages as a fact
+func (Invoice) TableName() string {
+	return "billing_invoices"
+}
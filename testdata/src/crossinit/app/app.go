@@ -0,0 +1,14 @@
+// Package app must not connect to anything at startup. // want package:"PackageEffectsFact\\{0 functions\\}"
+//
+// dirty: { }
+package app
+
+// Test case: the initialization of imported packages is part of the package initialization
+
+import (
+	"crossinit/db" // want `package initialization calls crossinit/db\.init which has effects \[network\[db\]\] not declared for this package`
+)
+
+func Port() int { // want Port:"FunctionEffectsFact\\[\\]"
+	return db.Conn
+}
@@ -0,0 +1,9 @@
+package db
+
+// dirty: { network[db] }
+func Connect() int {
+	return 0
+}
+
+// Conn is connected when the package is initialized
+var Conn = Connect()
@@ -0,0 +1,38 @@
+package handler // want package:"PackageEffectsFact\\{4 functions\\}"
+
+import (
+	"crossmethod/repo"
+	"crossmethod/service"
+)
+
+// Test case: method calls on values of types declared in other packages
+
+type Handler struct {
+	svc *service.UserService
+}
+
+// Valid: pointer method call on a field of an imported type
+// dirty: { select[users] | select[profiles] }
+func (h *Handler) Show(id int) string { // want Show:"FunctionEffectsFact\\[select\\[profiles\\] select\\[users\\]\\]"
+	return h.svc.GetUser(id)
+}
+
+// Invalid: missing select[profiles]
+// dirty: { select[users] }
+func (h *Handler) ShowBroken(id int) string { // want ShowBroken:"FunctionEffectsFact\\[select\\[profiles\\] select\\[users\\]\\]"
+	return h.svc.GetUser(id) // want `function calls crossmethod/service\.\(\*UserService\)\.GetUser which has effects \[select\[profiles\], select\[users\]\] not declared in this function`
+}
+
+// Invalid: value receiver method called through an exported field
+// dirty: { }
+func (h *Handler) Audit() { // want Audit:"FunctionEffectsFact\\[insert\\[audit_logs\\]\\]"
+	h.svc.Audit.Record("audit") // want `function calls crossmethod/service\.AuditLog\.Record which has effects \[insert\[audit_logs\]\] not declared in this function`
+}
+
+// Invalid: methods promoted from an embedded type in another package
+// dirty: { select[profiles] }
+func (h *Handler) Lookup(id int) string { // want Lookup:"FunctionEffectsFact\\[insert\\[users\\] select\\[profiles\\] select\\[users\\]\\]"
+	var r repo.UserRepository
+	_ = r.Create("name")      // want `function calls crossmethod/repo\.UserRepository\.Create which has effects \[insert\[users\]\] not declared in this function`
+	return h.svc.FindByID(id) // want `function calls crossmethod/repo\.\(\*UserRepository\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
@@ -0,0 +1,29 @@
+package indirect // want package:"PackageEffectsFact\\{4 functions\\}"
+
+import "crossmethod/service"
+
+// Test case: methods and contracts of a package imported only by an imported package
+
+// Invalid: methods promoted from a type whose package is not imported here
+// dirty: { }
+func Find(s *service.UserService) string { // want Find:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	return s.FindByID(1) // want `function calls crossmethod/repo\.\(\*UserRepository\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: methods called on a field of such a type
+// dirty: { }
+func FindThroughField(s *service.UserService) string { // want FindThroughField:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	return s.Repo.FindByID(1) // want `function calls crossmethod/repo\.\(\*UserRepository\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: the contract of an interface of such a package
+// dirty: { }
+func FindThroughInterface(s *service.UserService) string { // want FindThroughInterface:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	return s.Users.Find(1) // want `function calls crossmethod/repo\.Finder\.Find which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: the contract of a function type of such a package
+// dirty: { }
+func Remove(s *service.UserService) { // want Remove:"FunctionEffectsFact\\[delete\\[users\\]\\]"
+	s.Purge(1) // want `function calls crossmethod/repo\.Purge which has effects \[delete\[users\]\] not declared in this function`
+}
@@ -0,0 +1,23 @@
+package repo
+
+type UserRepository struct{}
+
+// dirty: { select[users] }
+func (r *UserRepository) FindByID(id int) string {
+	return "user"
+}
+
+// dirty: { insert[users] }
+func (r UserRepository) Create(name string) error {
+	return nil
+}
+
+// Finder finds users
+type Finder interface {
+	// dirty: { select[users] }
+	Find(id int) string
+}
+
+// Purge removes a user
+// dirty: { delete[users] }
+type Purge func(id int)
@@ -0,0 +1,22 @@
+package service
+
+import "crossmethod/repo"
+
+// UserService embeds the repository so its methods are promoted
+type UserService struct {
+	*repo.UserRepository
+	Audit AuditLog
+	Users repo.Finder
+	Purge repo.Purge
+	Repo  *repo.UserRepository
+}
+
+// dirty: { select[users] | select[profiles] }
+func (s *UserService) GetUser(id int) string {
+	return s.FindByID(id)
+}
+
+type AuditLog struct{}
+
+// dirty: { insert[audit_logs] }
+func (a AuditLog) Record(event string) {}
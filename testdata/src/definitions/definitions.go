@@ -0,0 +1,112 @@
+package definitions
+
+// Test case: named effect sets defined with //dirty-define:
+
+//dirty-define: userOps = { select[users] | update[users] }
+//dirty-define: auditOps = { insert[audit_logs] }
+//dirty-define: accountOps = { userOps | auditOps | delete[sessions] }
+
+// Valid: the set is expanded in the declaration
+// dirty: { userOps }
+func RenameUser() {
+	LoadUser()
+	SaveUser()
+}
+
+// dirty: { select[users] }
+func LoadUser() {}
+
+// dirty: { update[users] }
+func SaveUser() {}
+
+// Invalid: sets are combined with other effects
+// dirty: { userOps | network }
+func CloseAccount() {
+	RenameUser()
+	DeleteSessions() // want `function calls DeleteSessions which has effects \[delete\[sessions\]\] not declared in this function`
+}
+
+// dirty: { delete[sessions] }
+func DeleteSessions() {}
+
+// Invalid: sets referring to other sets
+// dirty: { accountOps }
+func CloseAccountAudited() {
+	CloseAccount() // want `function calls CloseAccount which has effects \[delete\[sessions\], network, select\[users\], update\[users\]\] not declared in this function`
+	DeleteSessions()
+	Audit()
+}
+
+// Audit is declared in the effect registry with a named set
+func Audit() {}
+
+// Invalid: the registry declaration is expanded too
+// dirty: { userOps }
+func AuditedRename() {
+	RenameUser()
+	Audit() // want `function calls Audit which has effects \[insert\[audit_logs\]\] not declared in this function`
+}
+
+//dirty-define: readOps = userOps \ { update[users] }
+//dirty-define: sharedOps = userOps & { update[users] | delete[sessions] }
+
+// Invalid: the difference removes the update
+// dirty: readOps
+func ReadOnlyRename() {
+	LoadUser()
+	SaveUser() // want `function calls SaveUser which has effects \[update\[users\]\] not declared in this function`
+}
+
+// Valid: operators at the top level of declarations
+// dirty: sharedOps | accountOps \ userOps
+func SaveAndDelete() {
+	SaveUser()
+	DeleteSessions()
+}
+
+// dirty: { delete[users] }
+func DropUser() {}
+
+// Invalid: the difference splits write into the operations it implies
+// dirty: { write[users] } \ { delete[users] }
+func Rewrite() {
+	SaveUser()
+	DropUser() // want `function calls DropUser which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// Valid: the intersection keeps the labels both sets cover
+// dirty: { select[*] } & { select[users] | delete[users] }
+func LoadOnly() {
+	LoadUser()
+}
+
+// Wildcards cannot be split
+// dirty: { *[users] } \ { delete[users] } // want `cannot remove delete\[users\] from \*\[users\]`
+func Unsplittable() {}
+
+//dirty-define: notDeleting = { *[users] } \ { delete[users] } // want `cannot remove delete\[users\] from \*\[users\]`
+
+// Declarations using a broken set are not reported again
+// dirty: { notDeleting }
+func UseUnsplittable() {}
+
+//dirty-define: loopA = { loopB | select[a] } // want `effect set loopA is defined in terms of itself: loopA -> loopB -> loopA`
+//dirty-define: loopB = { loopA }
+
+//dirty-define: broken = { Missing | select[b] } // want `undefined effect set Missing`
+
+//dirty-define: userOps = { select[accounts] } // want `effect set userOps is already defined`
+
+//dirty-define: = { select[c] } // want `invalid effect set definition: invalid name ""`
+
+// Capitalized names that are not defined are reported, lowercase ones are labels
+// dirty: { Undefined | transform } // want `undefined effect set Undefined`
+func Transform() {}
+
+// Lowercase names close to a defined set are misspellings rather than labels
+// dirty: { userOpz } // want `undefined effect set userOpz \(did you mean userOps\?\)`
+func Misspelled() {}
+
+// Declarations referring to broken sets have no effects of their own
+// dirty: { loopB }
+func Loop() {}
@@ -0,0 +1,102 @@
+package embedding
+
+// Test case: methods promoted through struct embedding
+
+type UserRepository struct{}
+
+// dirty: { select[users] }
+func (r *UserRepository) FindByID(id int) string {
+	return ""
+}
+
+// dirty: { insert[users] }
+func (r UserRepository) Create(name string) error {
+	return nil
+}
+
+// A local function of the same name must not be mistaken for the method
+// dirty: { delete[everything] }
+func FindByID(id int) string {
+	return ""
+}
+
+type UserService struct {
+	*UserRepository
+}
+
+type AdminService struct {
+	UserService
+}
+
+// Valid: the promoted method of the embedded pointer type is called
+// dirty: { select[users] }
+func (s *UserService) Show(id int) string {
+	return s.FindByID(id)
+}
+
+// Invalid: missing the effects of the promoted method
+// dirty: { }
+func (s *UserService) ShowBroken(id int) string {
+	return s.FindByID(id) // want `function calls \(\*UserRepository\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: methods are promoted through several levels of embedding
+// dirty: { select[users] }
+func (a *AdminService) Register(name string) error {
+	_ = a.FindByID(1)
+	return a.Create(name) // want `function calls UserRepository\.Create which has effects \[insert\[users\]\] not declared in this function`
+}
+
+// Invalid: promoted method values are followed too
+// dirty: { }
+func (a *AdminService) Lookup() {
+	find := a.FindByID
+	find(1) // want `function calls \(\*UserRepository\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+type Reader interface {
+	// dirty: { select[users] }
+	Read(id int) string
+}
+
+type Writer interface {
+	// dirty: { insert[users] }
+	Write(name string) error
+}
+
+// ReadWriter embeds the contracts of Reader and Writer
+type ReadWriter interface {
+	Reader
+	Writer
+}
+
+// Invalid: methods of embedded interfaces have the effects of their contracts
+// dirty: { select[users] }
+func Copy(rw ReadWriter) error {
+	return rw.Write(rw.Read(1)) // want `function calls Writer\.Write which has effects \[insert\[users\]\] not declared in this function`
+}
+
+// Store embeds an interface, promoting its methods
+type Store struct {
+	Reader
+}
+
+// Invalid: methods promoted from an embedded interface have the effects of its contract
+// dirty: { }
+func (s Store) Get(id int) string {
+	return s.Read(id) // want `function calls Reader\.Read which has effects \[select\[users\]\] not declared in this function`
+}
+
+type fileReader struct{}
+
+// dirty: { read[files] }
+func (fileReader) Read(id int) string {
+	return ""
+}
+
+type cachedReader struct {
+	fileReader
+}
+
+// Invalid: a promoted method implementing an interface is checked against its contract
+var _ Reader = cachedReader{} // want `method fileReader\.Read has effects \[read\[files\]\] not declared in the contract of Reader\.Read`
@@ -0,0 +1,72 @@
+// Package ent is a stub of a package generated by ent
+package ent
+
+import (
+	"context"
+
+	"enteffects/ent/pet"
+	"enteffects/ent/user"
+
+	"entgo.io/ent"
+)
+
+var _ ent.Hook
+
+var _ = user.Table
+var _ = pet.Table
+
+type Client struct {
+	User *UserClient
+	Pet  *PetClient
+}
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type Pet struct {
+	ID int
+}
+
+type UserClient struct{}
+
+func (c *UserClient) Create() *UserCreate                            { return &UserCreate{} }
+func (c *UserClient) Query() *UserQuery                              { return &UserQuery{} }
+func (c *UserClient) UpdateOneID(id int) *UserUpdateOne              { return &UserUpdateOne{} }
+func (c *UserClient) Delete() *UserDelete                            { return &UserDelete{} }
+func (c *UserClient) Get(ctx context.Context, id int) (*User, error) { return nil, nil }
+func (c *UserClient) QueryPets(u *User) *PetQuery                    { return &PetQuery{} }
+
+type UserCreate struct{}
+
+func (uc *UserCreate) SetName(s string) *UserCreate            { return uc }
+func (uc *UserCreate) Save(ctx context.Context) (*User, error) { return nil, nil }
+func (uc *UserCreate) OnConflict() *UserUpsertOne              { return &UserUpsertOne{} }
+
+type UserUpsertOne struct{}
+
+func (u *UserUpsertOne) UpdateNewValues() *UserUpsertOne { return u }
+func (u *UserUpsertOne) Exec(ctx context.Context) error  { return nil }
+
+type UserQuery struct{}
+
+func (uq *UserQuery) Where(ps ...func()) *UserQuery            { return uq }
+func (uq *UserQuery) All(ctx context.Context) ([]*User, error) { return nil, nil }
+func (uq *UserQuery) Count(ctx context.Context) (int, error)   { return 0, nil }
+
+type UserUpdateOne struct{}
+
+func (uuo *UserUpdateOne) SetName(s string) *UserUpdateOne         { return uuo }
+func (uuo *UserUpdateOne) Save(ctx context.Context) (*User, error) { return nil, nil }
+
+type UserDelete struct{}
+
+func (ud *UserDelete) Where(ps ...func()) *UserDelete        { return ud }
+func (ud *UserDelete) Exec(ctx context.Context) (int, error) { return 0, nil }
+
+type PetClient struct{}
+
+type PetQuery struct{}
+
+func (pq *PetQuery) All(ctx context.Context) ([]*Pet, error) { return nil, nil }
@@ -0,0 +1,9 @@
+// Package pet is a stub of the package ent generates for the Pet entity
+package pet
+
+const (
+	// Label holds the string label denoting the pet type in the database.
+	Label = "pet"
+	// Table holds the table name of the pet in the database, set by a schema annotation.
+	Table = "animals"
+)
@@ -0,0 +1,9 @@
+// Package user is a stub of the package ent generates for the User entity
+package user
+
+const (
+	// Label holds the string label denoting the user type in the database.
+	Label = "user"
+	// Table holds the table name of the user in the database.
+	Table = "users"
+)
@@ -0,0 +1,42 @@
+package enteffects
+
+import (
+	"context"
+
+	"enteffects/ent"
+)
+
+// Test case: database effects inferred from ent builders
+
+// Valid: the query builder reads users
+// dirty: { select[users] }
+func ListUsers(ctx context.Context, client *ent.Client) ([]*ent.User, error) {
+	return client.User.Query().Where().All(ctx)
+}
+
+// Invalid: the create builder inserts users
+// dirty: { select[users] }
+func CreateUser(ctx context.Context, client *ent.Client) (*ent.User, error) {
+	return client.User.Create().SetName("alice").Save(ctx) // want `function calls enteffects/ent\.\(\*UserCreate\)\.Save which has effects \[insert\[users\]\] not declared in this function`
+}
+
+// Invalid: updates, deletes and upserts
+// dirty: { update[users] }
+func Rename(ctx context.Context, client *ent.Client, id int) error {
+	if _, err := client.User.UpdateOneID(id).SetName("bob").Save(ctx); err != nil {
+		return err
+	}
+	if _, err := client.User.Delete().Where().Exec(ctx); err != nil { // want `function calls enteffects/ent\.\(\*UserDelete\)\.Exec which has effects \[delete\[users\]\] not declared in this function`
+		return err
+	}
+	return client.User.Create().OnConflict().UpdateNewValues().Exec(ctx) // want `function calls enteffects/ent\.\(\*UserUpsertOne\)\.Exec which has effects \[insert\[users\], update\[users\]\] not declared in this function`
+}
+
+// Invalid: edges are read from the table of their schema
+// dirty: { select[users] }
+func Pets(ctx context.Context, client *ent.Client, u *ent.User) ([]*ent.Pet, error) {
+	if _, err := client.User.Get(ctx, u.ID); err != nil {
+		return nil, err
+	}
+	return client.User.QueryPets(u).All(ctx) // want `function calls enteffects/ent\.\(\*PetQuery\)\.All which has effects \[select\[animals\]\] not declared in this function`
+}
@@ -0,0 +1,5 @@
+// Package ent is a stub of entgo.io/ent for tests
+package ent
+
+// Hook is a mutation hook
+type Hook func()
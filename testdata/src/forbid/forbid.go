@@ -0,0 +1,69 @@
+package forbid
+
+// Test case: effects forbidden with //dirty-forbid:
+
+// dirty: { network }
+func Fetch() {}
+
+// dirty: { select[users] }
+func LoadUser() {}
+
+// dirty: { delete[users] }
+func DeleteUser() {}
+
+// dirty: { *[users] }
+func ManageUser() {}
+
+func fetchAll() {
+	Fetch()
+}
+
+// Invalid: forbidden effects of transitive callees are reported without a declaration
+// dirty-forbid: { network | delete[*] }
+func Pure() {
+	LoadUser()
+	fetchAll()   // want `function calls fetchAll which has effects \[network\] forbidden in this function`
+	DeleteUser() // want `function calls DeleteUser which has effects \[delete\[users\]\] forbidden in this function`
+	ManageUser() // want `function calls ManageUser which has effects \[\*\[users\]\] forbidden in this function`
+}
+
+// dirty: { network[mailer] }
+func SendMail() {}
+
+// Invalid: a label without a target forbids every target of its operation
+// dirty-forbid: { network }
+func Notify() {
+	SendMail()    // want `function calls SendMail which has effects \[network\[mailer\]\] forbidden in this function`
+	go SendMail() // want `function calls SendMail which has effects \[network\[mailer\]\] forbidden in this function`
+}
+
+// Valid: forbidden effects may be declared with other effects
+// dirty: { select[users] }
+// dirty-forbid: { network }
+func Load() {
+	LoadUser()
+}
+
+// Invalid: the declaration itself has a forbidden effect
+// dirty: { network } // want `function declares effects \[network\] forbidden in this function`
+// dirty-forbid: { network }
+func Contradiction() {}
+
+//dirty-define: writes = { insert[users] | update[users] | delete[users] }
+
+// Cache must never change the database
+// dirty-forbid: { writes }
+type Cache struct{}
+
+// Get is reported where the effect comes in, not at each method of the type
+func (c *Cache) Get() {
+	c.load()
+}
+
+func (c *Cache) load() {
+	LoadUser()
+	DeleteUser() // want `function calls DeleteUser which has effects \[delete\[users\]\] forbidden for type Cache`
+}
+
+// dirty-forbid: { network } async { } // want `invalid forbidden effects: unexpected async clause`
+func Invalid() {}
@@ -0,0 +1,19 @@
+// Package forbidpkg must never use the network.
+//
+// dirty-forbid: { network }
+package forbidpkg
+
+// dirty: { network } // want `function declares effects \[network\] forbidden in this package`
+func fetch() {}
+
+func load() {
+	fetch()
+}
+
+// Valid: the effect is reported where it comes in, not at each function of the package
+func Show() {
+	load()
+	func() {
+		load()
+	}()
+}
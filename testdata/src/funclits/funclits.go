@@ -0,0 +1,104 @@
+package funclits
+
+// Test case: function literals are analysis units of their own
+
+// dirty: { select[users] }
+func selectUsers() {}
+
+// dirty: { insert[users] }
+func insertUser() {}
+
+// dirty: { delete[users] }
+func deleteUsers() {}
+
+// Invalid: an immediately invoked literal runs in its enclosing function
+// dirty: { }
+func Immediate() {
+	func() { // want `function calls Immediate\$1 which has effects \[delete\[users\]\] not declared in this function`
+		deleteUsers()
+	}()
+}
+
+// Valid: the literal is only returned, so its effects belong to whoever calls it
+// dirty: { }
+func MakeDeleter() func() {
+	return func() {
+		deleteUsers()
+	}
+}
+
+// Invalid: calling the returned literal
+// dirty: { }
+func RunDeleter() {
+	deleter := MakeDeleter()
+	deleter() // want `function calls MakeDeleter\$1 which has effects \[delete\[users\]\] not declared in this function`
+}
+
+type Job struct {
+	Run func()
+}
+
+// Valid: the literal is only stored in a struct field
+// dirty: { }
+func NewJob() Job {
+	return Job{Run: func() { insertUser() }}
+}
+
+// Invalid: calling the struct field
+// dirty: { select[users] }
+func RunJob(job Job) {
+	job.Run() // want `function calls NewJob\$1 which has effects \[insert\[users\]\] not declared in this function`
+}
+
+var onExit func()
+
+// Valid: the literal is stored but never called here
+// dirty: { }
+func RegisterCleanup() {
+	onExit = func() {
+		deleteUsers()
+	}
+}
+
+// Invalid: the variable holds the literal stored by RegisterCleanup
+// dirty: { }
+func Exit() {
+	onExit() // want `function calls RegisterCleanup\$1 which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// Annotated literals are checked against their own declaration
+// dirty: { select[users] }
+func Annotated() {
+	// dirty: { select[users] }
+	fetch := func() {
+		selectUsers()
+	}
+	fetch()
+
+	// dirty: { }
+	_ = func() {
+		deleteUsers() // want `function calls deleteUsers which has effects \[delete\[users\]\] not declared in this function`
+	}
+}
+
+func forEach(items []int, f func(int)) {
+	for _, item := range items {
+		f(item)
+	}
+}
+
+// Invalid: a literal passed to a function is called through its parameter
+// dirty: { }
+func DeleteEach(items []int) {
+	forEach(items, func(int) { deleteUsers() }) // want `function calls forEach which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// Literals in package-level declarations belong to the package initialization
+var cleanup = func() {
+	deleteUsers()
+}
+
+// dirty: { }
+func Cleanup() {
+	cleanup() // want `function calls init\$1 which has effects \[delete\[users\]\] not declared in this function`
+}
@@ -0,0 +1,68 @@
+package funcrefs
+
+// Test case: function references, method values and method expressions
+
+type Repo struct{}
+
+// dirty: { select[users] }
+func (r *Repo) FindByID(id int) string {
+	return ""
+}
+
+// dirty: { delete[users] }
+func (r *Repo) Delete(id int) {}
+
+// dirty: { f | begin[tx] }
+func (r *Repo) WithTx(f func()) {}
+
+// dirty: { delete[cache] }
+func purge() {}
+
+// Invalid: a function reference stored in a variable
+// dirty: { }
+func FuncRef() {
+	f := purge
+	f() // want `function calls purge which has effects \[delete\[cache\]\] not declared in this function`
+}
+
+// Invalid: a method value stored in a variable
+// dirty: { }
+func MethodValue(r *Repo) {
+	find := r.FindByID
+	find(1) // want `function calls \(\*Repo\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: a method expression called directly and through a variable
+// dirty: { }
+func MethodExpr(r *Repo) {
+	(*Repo).FindByID(r, 1) // want `function calls \(\*Repo\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+	find := (*Repo).FindByID
+	find(r, 1) // want `function calls \(\*Repo\)\.FindByID which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Effect parameters are instantiated through method expressions too
+// dirty: { begin[tx] }
+func MethodExprWithTx(r *Repo) {
+	(*Repo).WithTx(r, purge) // want `function calls \(\*Repo\)\.WithTx which has effects \[begin\[tx\], delete\[cache\]\] not declared in this function`
+}
+
+func apply(f func(int)) {
+	f(1)
+}
+
+// Invalid: the method value is called by apply through its parameter
+// dirty: { }
+func PassMethodValue(r *Repo) {
+	apply(r.Delete) // want `function calls apply which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// The effects of a function passed as an argument belong to the function calling it
+// dirty: { }
+func run(f func(int)) {
+	f(2) // want `function calls \(\*Repo\)\.Delete which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// dirty: { delete[users] }
+func RunDelete(r *Repo) {
+	run(r.Delete)
+}
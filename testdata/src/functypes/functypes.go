@@ -0,0 +1,115 @@
+package functypes
+
+// Test case: effect contracts on function types and function-typed struct fields
+
+// dirty: { select[users] }
+func selectUsers() {}
+
+// dirty: { delete[users] }
+func deleteUsers() {}
+
+// dirty: { insert[audit_logs] }
+func audit() {}
+
+// Handler handles a request for a user
+// dirty: { select[users] }
+type Handler func(id int) error
+
+type Hooks struct {
+	// dirty: { insert[audit_logs] }
+	OnSave func()
+	OnLoad func() // Without a contract the stored functions are tracked
+}
+
+// dirty: { select[users] }
+func show(id int) error {
+	selectUsers()
+	return nil
+}
+
+// dirty: { select[users] | delete[users] }
+func remove(id int) error {
+	selectUsers()
+	deleteUsers()
+	return nil
+}
+
+// Valid: calling a Handler has the effects of its contract
+// dirty: { select[users] }
+func Serve(h Handler) error {
+	return h(1)
+}
+
+// Invalid: missing the effects of the contract
+// dirty: { }
+func ServeBroken(h Handler) error {
+	return h(1) // want `function calls Handler which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Valid: show respects the contract of Handler
+var showHandler Handler = show
+
+// Invalid: remove exceeds the contract of Handler
+var removeHandler Handler = remove // want `function remove has effects \[delete\[users\]\] not declared in the contract of Handler`
+
+// Conversions and arguments are checked as well. The literal passed to Serve is
+// called through the contract, so its effects are not charged to Register.
+// dirty: { select[users] }
+func Register() error {
+	_ = Handler(remove)               // want `function remove has effects \[delete\[users\]\] not declared in the contract of Handler`
+	return Serve(func(id int) error { // want `function Register\$1 has effects \[delete\[users\]\] not declared in the contract of Handler`
+		deleteUsers()
+		return nil
+	})
+}
+
+// Valid: calling a field with a contract has the effects of its contract
+// dirty: { insert[audit_logs] }
+func Save(h Hooks) {
+	h.OnSave()
+}
+
+// dirty: { }
+func SaveBroken(h Hooks) {
+	h.OnSave() // want `function calls Hooks\.OnSave which has effects \[insert\[audit_logs\]\] not declared in this function`
+}
+
+func NewHooks() Hooks {
+	return Hooks{
+		OnSave: audit,
+		OnLoad: func() { deleteUsers() },
+	}
+}
+
+func NewHooksBroken() Hooks {
+	return Hooks{
+		OnSave: deleteUsers, // want `function deleteUsers has effects \[delete\[users\]\] not declared in the contract of Hooks\.OnSave`
+	}
+}
+
+// dirty: { }
+func Load(h Hooks) {
+	h.OnLoad() // want `function calls NewHooks\$1 which has effects \[delete\[users\]\] not declared in this function`
+}
+
+// dirty: { delete[users] }
+func purge(id int) error {
+	deleteUsers()
+	return nil
+}
+
+// Invalid: returned values, channel sends and elements of slices and maps have the
+// contract of their type
+func NewPurgeHandler() Handler {
+	return purge // want `function purge has effects \[delete\[users\]\] not declared in the contract of Handler`
+}
+
+var handlers = []Handler{purge} // want `function purge has effects \[delete\[users\]\] not declared in the contract of Handler`
+
+var routes = map[string]Handler{
+	"/purge": purge, // want `function purge has effects \[delete\[users\]\] not declared in the contract of Handler`
+}
+
+func Enqueue(ch chan<- Handler) {
+	ch <- purge // want `function purge has effects \[delete\[users\]\] not declared in the contract of Handler`
+}
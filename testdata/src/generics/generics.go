@@ -0,0 +1,142 @@
+package generics
+
+// Test case: calls of methods on type parameters
+
+type Saver interface {
+	// dirty: { insert[records] }
+	Save() error
+}
+
+type User struct{}
+
+// dirty: { insert[records] }
+func (u *User) Save() error {
+	return nil
+}
+
+type Post struct{}
+
+// dirty: { insert[records] | delete[cache] }
+func (p *Post) Save() error { // different effects from the contract
+	return nil
+}
+
+type Draft struct{}
+
+// dirty: { }
+func (d *Draft) Save() error {
+	return nil
+}
+
+// A local function of the same name must not be mistaken for the method
+// dirty: { delete[everything] }
+func Save() {}
+
+// Valid: calls on the type parameter have the effects of the constraint's contract
+// dirty: { insert[records] }
+func Store[T Saver](t T) error {
+	return t.Save()
+}
+
+// Invalid: missing the effects of the constraint's contract
+// dirty: { }
+func StoreBroken[T Saver](t T) error {
+	return t.Save() // want `function calls Saver\.Save which has effects \[insert\[records\]\] not declared in this function`
+}
+
+// dirty: { select[records] }
+func load() {}
+
+// Undeclared generic functions get the effects of the type argument's method
+func saveAll[T Saver](items []T) {
+	load()
+	for _, item := range items {
+		item.Save()
+	}
+}
+
+// Valid: the Draft methods have no effects, so only load's effects remain
+// dirty: { select[records] }
+func SaveDrafts(drafts []*Draft) {
+	saveAll(drafts)
+}
+
+// dirty: { }
+func SaveDraftsBroken(drafts []*Draft) {
+	saveAll(drafts) // want `function calls saveAll which has effects \[select\[records\]\] not declared in this function`
+}
+
+// dirty: { select[records] }
+func SaveUsers(users []*User) {
+	saveAll(users) // want `function calls saveAll which has effects \[insert\[records\], select\[records\]\] not declared in this function`
+}
+
+// Invalid: Post's method exceeds the contract of Saver
+// dirty: { insert[records] | delete[cache] }
+func StorePost(p *Post) error {
+	return Store(p) // want `method \(\*Post\)\.Save has effects \[delete\[cache\]\] not declared in the contract of Saver\.Save`
+}
+
+// Explicit instantiations are checked as well
+var storePost = Store[*Post] // want `method \(\*Post\)\.Save has effects \[delete\[cache\]\] not declared in the contract of Saver\.Save`
+
+// Undeclared methods of generic types get the effects of the type argument's method
+// at each call
+type Queue[T Saver] struct {
+	items []T
+}
+
+func (q *Queue[T]) Flush() {
+	load()
+	for _, item := range q.items {
+		item.Save()
+	}
+}
+
+// Valid: the Draft methods have no effects, so only load's effects remain
+// dirty: { select[records] }
+func FlushDrafts(q *Queue[*Draft]) {
+	q.Flush()
+}
+
+// dirty: { select[records] }
+func FlushUsers(q *Queue[*User]) {
+	q.Flush() // want `function calls \(\*Queue\)\.Flush which has effects \[insert\[records\], select\[records\]\] not declared in this function`
+}
+
+// Undeclared generic functions passing their type parameters on to other generic
+// functions get the effects of the type argument's method as well
+func wrap[T Saver](item T) {
+	saveAll([]T{item})
+}
+
+func wrapTwice[T Saver](item T) {
+	wrap(item)
+}
+
+// Valid: the Draft methods have no effects, so only load's effects remain
+// dirty: { select[records] }
+func WrapDraft(d *Draft) {
+	wrap(d)
+}
+
+// dirty: { select[records] }
+func WrapUser(u *User) {
+	wrap(u) // want `function calls wrap which has effects \[insert\[records\], select\[records\]\] not declared in this function`
+}
+
+// dirty: { select[records] }
+func WrapDraftTwice(d *Draft) {
+	wrapTwice(d)
+}
+
+// dirty: { select[records] }
+func WrapUserTwice(u *User) {
+	wrapTwice(u) // want `function calls wrapTwice which has effects \[insert\[records\], select\[records\]\] not declared in this function`
+}
+
+// Valid: calls in declared generic functions have the effects of the constraint's contract
+// dirty: { select[records] | insert[records] }
+func WrapDeclared[T Saver](item T) {
+	wrap(item)
+}
@@ -0,0 +1,30 @@
+// Package sarama is a stub of github.com/IBM/sarama for tests
+package sarama
+
+import "context"
+
+type Encoder interface{}
+
+type StringEncoder string
+
+type ProducerMessage struct {
+	Topic string
+	Value Encoder
+}
+
+type SyncProducer interface {
+	SendMessage(msg *ProducerMessage) (partition int32, offset int64, err error)
+	SendMessages(msgs []*ProducerMessage) error
+}
+
+type PartitionConsumer interface{}
+
+type Consumer interface {
+	ConsumePartition(topic string, partition int32, offset int64) (PartitionConsumer, error)
+}
+
+type ConsumerGroupHandler interface{}
+
+type ConsumerGroup interface {
+	Consume(ctx context.Context, topics []string, handler ConsumerGroupHandler) error
+}
@@ -0,0 +1,20 @@
+// Package pgx is a stub of github.com/jackc/pgx/v5 for tests
+package pgx
+
+import "context"
+
+type Conn struct{}
+
+type Rows interface {
+	Close()
+}
+
+type CommandTag struct{}
+
+func (c *Conn) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return nil, nil
+}
+
+func (c *Conn) Exec(ctx context.Context, sql string, arguments ...any) (CommandTag, error) {
+	return CommandTag{}, nil
+}
@@ -0,0 +1,27 @@
+// Package sqlx is a stub of github.com/jmoiron/sqlx for tests
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type DB struct {
+	*sql.DB
+}
+
+func (db *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func (db *DB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func Get(q *DB, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package nats is a stub of github.com/nats-io/nats.go for tests
+package nats
+
+type Msg struct {
+	Subject string
+	Data    []byte
+}
+
+type MsgHandler func(msg *Msg)
+
+type Subscription struct{}
+
+type Conn struct{}
+
+func Connect(url string) (*Conn, error) { return &Conn{}, nil }
+
+func (nc *Conn) Publish(subj string, data []byte) error                      { return nil }
+func (nc *Conn) PublishMsg(m *Msg) error                                     { return nil }
+func (nc *Conn) Subscribe(subj string, cb MsgHandler) (*Subscription, error) { return nil, nil }
+func (nc *Conn) QueueSubscribe(subj, queue string, cb MsgHandler) (*Subscription, error) {
+	return nil, nil
+}
@@ -0,0 +1,75 @@
+package gormeffects
+
+import "gorm.io/gorm"
+
+// Test case: database effects inferred from GORM method chains
+
+type User struct {
+	ID   int64
+	Name string
+}
+
+type OrderItem struct {
+	ID int64
+}
+
+type Person struct {
+	ID int64
+}
+
+type Account struct {
+	ID int64
+}
+
+// TableName overrides the naming strategy
+func (Account) TableName() string {
+	return "billing_accounts"
+}
+
+// Valid: the model sets the table and Find reads it
+// dirty: { select[users] }
+func ListUsers(db *gorm.DB) []User {
+	var users []User
+	db.Model(&User{}).Where("name = ?", "alice").Order("id").Find(&users)
+	return users
+}
+
+// Invalid: the table of the created value
+// dirty: { select[users] }
+func AddItem(db *gorm.DB, item *OrderItem) error {
+	return db.Create(item).Error // want `function calls gorm\.io/gorm\.\(\*DB\)\.Create which has effects \[insert\[order_items\]\] not declared in this function`
+}
+
+// Invalid: Save inserts or updates, with irregular plurals
+// dirty: { insert[people] }
+func SavePerson(db *gorm.DB, p Person) {
+	db.Save(&p) // want `function calls gorm\.io/gorm\.\(\*DB\)\.Save which has effects \[insert\[people\], update\[people\]\] not declared in this function`
+}
+
+// Invalid: TableName methods name the table
+// dirty: { }
+func CloseAccount(db *gorm.DB, id int64) {
+	db.Delete(&Account{}, id) // want `function calls gorm\.io/gorm\.\(\*DB\)\.Delete which has effects \[delete\[billing_accounts\]\] not declared in this function`
+}
+
+// Invalid: Table names the table, and Count reads it
+// dirty: { }
+func CountSessions(db *gorm.DB) int64 {
+	var n int64
+	db.Table("sessions").Count(&n) // want `function calls gorm\.io/gorm\.\(\*DB\)\.Count which has effects \[select\[sessions\]\] not declared in this function`
+	return n
+}
+
+// Invalid: updates through the model of the chain
+// dirty: { select[users] }
+func Rename(db *gorm.DB, id int64, name string) {
+	db.Model(&User{ID: id}).Update("name", name) // want `function calls gorm\.io/gorm\.\(\*DB\)\.Update which has effects \[update\[users\]\] not declared in this function`
+}
+
+// Valid: the effects of Raw come from its SQL
+// dirty: { select[users] }
+func RawUsers(db *gorm.DB) []User {
+	var users []User
+	db.Raw("SELECT * FROM users WHERE id > ?", 10).Scan(&users)
+	return users
+}
@@ -0,0 +1,73 @@
+package hierarchy
+
+// Test case: hierarchical targets and the operation hierarchy
+
+// dirty: { select[db.users.email] }
+func LoadEmail() {}
+
+// dirty: { select[db.users] }
+func LoadUser() {}
+
+// dirty: { select[db.orders] }
+func LoadOrder() {}
+
+// dirty: { select[db.usersettings] }
+func LoadSettings() {}
+
+// dirty: { insert[db.users] }
+func CreateUser() {}
+
+// dirty: { update[db.users.email] }
+func ChangeEmail() {}
+
+// dirty: { delete[db.users] }
+func DeleteUser() {}
+
+// Valid: a target covers the targets below it
+// dirty: { select[db.users] }
+func ShowUser() {
+	LoadUser()
+	LoadEmail()
+}
+
+// Valid: db covers every table
+// dirty: { select[db] }
+func Report() {
+	LoadUser()
+	LoadOrder()
+	LoadSettings()
+}
+
+// Invalid: db.users does not cover db.usersettings, a sibling with a longer name
+// dirty: { select[db.users] }
+func ShowSettings() {
+	LoadSettings() // want `function calls LoadSettings which has effects \[select\[db\.usersettings\]\] not declared in this function`
+}
+
+// Invalid: a target does not cover the targets above it
+// dirty: { select[db.users.email] }
+func ShowEmail() {
+	LoadEmail()
+	LoadUser() // want `function calls LoadUser which has effects \[select\[db\.users\]\] not declared in this function`
+}
+
+// Valid: write implies insert, update and delete, on targets below too
+// dirty: { write[db.users] }
+func ManageUser() {
+	CreateUser()
+	ChangeEmail()
+	DeleteUser()
+}
+
+// Invalid: write does not imply select
+// dirty: { write[db] }
+func SyncUser() {
+	LoadUser() // want `function calls LoadUser which has effects \[select\[db\.users\]\] not declared in this function`
+	CreateUser()
+}
+
+// Invalid: the implied operations do not cover the operation implying them
+// dirty: { insert[db.users] | update[db.users] | delete[db.users] }
+func Admin() {
+	ManageUser() // want `function calls ManageUser which has effects \[delete\[db\.users\], insert\[db\.users\], update\[db\.users\.email\], write\[db\.users\]\] not declared in this function`
+}
@@ -0,0 +1,45 @@
+// Package initeffects tests the effects of package initialization.
+//
+// dirty: { select[config] }
+package initeffects
+
+// Test case: package initialization runs variable initializers and init functions
+
+// dirty: { select[config] }
+func loadConfig() string {
+	return ""
+}
+
+// dirty: { network[metrics] }
+func dialMetrics() int {
+	return 0
+}
+
+// dirty: { insert[audit_logs] }
+func audit() {}
+
+// Valid: declared in the package doc
+var config = loadConfig()
+
+// Invalid: undeclared effects of a variable initializer
+var metrics = dialMetrics() // want `package initialization calls dialMetrics which has effects \[network\[metrics\]\] not declared for this package`
+
+// Valid: the literal is only stored, not run at initialization
+var reload = func() int {
+	return dialMetrics()
+}
+
+// Invalid: immediately invoked literals run at initialization
+var port = func() int { // want `package initialization calls init\$2 which has effects \[network\[metrics\]\] not declared for this package`
+	return dialMetrics()
+}()
+
+func init() { // want `package initialization calls init#1 which has effects \[insert\[audit_logs\]\] not declared for this package`
+	audit()
+}
+
+// Declared init functions are checked against their own declaration
+// dirty: { select[config] }
+func init() {
+	_ = loadConfig()
+}
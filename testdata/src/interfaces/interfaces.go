@@ -0,0 +1,91 @@
+package interfaces
+
+// Test case: effect contracts on interface methods
+
+type UserRepository interface {
+	// dirty: { select[users] }
+	FindByID(id int64) (string, error)
+
+	// dirty: { insert[users] }
+	Create(name string) error
+
+	// Methods without a contract are not tracked
+	Close() error
+}
+
+type SQLUserRepository struct{}
+
+// dirty: { select[users] }
+func (r *SQLUserRepository) FindByID(id int64) (string, error) {
+	return "", nil
+}
+
+// dirty: { insert[users] | insert[audit_logs] }
+func (r *SQLUserRepository) Create(name string) error {
+	return nil
+}
+
+// dirty: { network[db] }
+func (r *SQLUserRepository) Close() error {
+	return nil
+}
+
+// Invalid: Create exceeds its contract
+var _ UserRepository = (*SQLUserRepository)(nil) // want `method \(\*SQLUserRepository\)\.Create has effects \[insert\[audit_logs\]\] not declared in the contract of UserRepository\.Create`
+
+type MemoryUserRepository struct{}
+
+func (r MemoryUserRepository) FindByID(id int64) (string, error) {
+	return "", nil
+}
+
+// dirty: { insert[users] }
+func (r MemoryUserRepository) Create(name string) error {
+	return nil
+}
+
+func (r MemoryUserRepository) Close() error {
+	return nil
+}
+
+// Valid: every method stays within its contract
+var _ UserRepository = MemoryUserRepository{}
+
+// Valid: the call through the interface has the contract's effects
+// dirty: { select[users] }
+func ShowUser(repo UserRepository) error {
+	_, err := repo.FindByID(1)
+	return err
+}
+
+// Invalid: missing the contract's effect
+// dirty: { select[users] }
+func CreateUser(repo UserRepository) error {
+	return repo.Create("name") // want `function calls UserRepository\.Create which has effects \[insert\[users\]\] not declared in this function`
+}
+
+// Calls without a contract contribute no effects
+// dirty: { }
+func CloseRepository(repo UserRepository) error {
+	return repo.Close()
+}
+
+func Setup() UserRepository {
+	var repo UserRepository
+	repo = &SQLUserRepository{} // want `method \(\*SQLUserRepository\)\.Create has effects \[insert\[audit_logs\]\] not declared in the contract of UserRepository\.Create`
+	repo = MemoryUserRepository{}
+	return repo
+}
+
+// Invalid: returned values, channel sends and elements of slices and maps are
+// assigned to the interface
+func NewRepository() UserRepository {
+	return &SQLUserRepository{} // want `method \(\*SQLUserRepository\)\.Create has effects \[insert\[audit_logs\]\] not declared in the contract of UserRepository\.Create`
+}
+
+var repositories = [...]UserRepository{&SQLUserRepository{}, MemoryUserRepository{}} // want `method \(\*SQLUserRepository\)\.Create has effects \[insert\[audit_logs\]\] not declared in the contract of UserRepository\.Create`
+
+func Publish(ch chan UserRepository) {
+	ch <- &SQLUserRepository{} // want `method \(\*SQLUserRepository\)\.Create has effects \[insert\[audit_logs\]\] not declared in the contract of UserRepository\.Create`
+	ch <- MemoryUserRepository{}
+}
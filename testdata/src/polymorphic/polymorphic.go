@@ -0,0 +1,70 @@
+package polymorphic
+
+// Test case: effect polymorphism for higher-order functions
+
+type Tx struct{}
+
+// The effect variable e stands for the effects of the callback passed as e
+// dirty: { e | begin[tx] | commit[tx] }
+func WithTx(e func(tx *Tx) error) error {
+	return e(&Tx{})
+}
+
+// dirty: { f | sleep }
+func Retry(attempts int, f func() error) error {
+	return f()
+}
+
+// dirty: { insert[users] }
+func insertUser(tx *Tx) error {
+	return nil
+}
+
+type AuditLog struct{}
+
+// dirty: { insert[audit_logs] }
+func (a *AuditLog) Record(tx *Tx) error {
+	return nil
+}
+
+// Valid: the callback's effects are declared
+// dirty: { insert[users] | begin[tx] | commit[tx] }
+func CreateUser() error {
+	return WithTx(insertUser)
+}
+
+// Invalid: missing the effects of the callback
+// dirty: { begin[tx] | commit[tx] }
+func CreateUserBroken() error {
+	return WithTx(insertUser) // want `function calls WithTx which has effects \[begin\[tx\], commit\[tx\], insert\[users\]\] not declared in this function`
+}
+
+// Invalid: method values are instantiated too
+// dirty: { insert[users] | begin[tx] | commit[tx] }
+func RecordAudit(log *AuditLog) error {
+	return WithTx(log.Record) // want `function calls WithTx which has effects \[begin\[tx\], commit\[tx\], insert\[audit_logs\]\] not declared in this function`
+}
+
+// Valid: a polymorphic function passing its own effect parameter along
+// dirty: { f | begin[tx] | commit[tx] | sleep }
+func RetryTx(f func(tx *Tx) error) error {
+	return Retry(3, func() error {
+		return WithTx(f)
+	})
+}
+
+// Invalid: nested instantiation
+// dirty: { begin[tx] | commit[tx] | sleep }
+func CreateUserWithRetry() error {
+	return RetryTx(insertUser) // want `function calls RetryTx which has effects \[begin\[tx\], commit\[tx\], insert\[users\], sleep\] not declared in this function`
+}
+
+// Without a declaration the instantiated effects are computed implicitly
+func createUserImplicitly() error {
+	return WithTx(insertUser)
+}
+
+// dirty: { begin[tx] | commit[tx] }
+func CallImplicit() error {
+	return createUserImplicitly() // want `function calls createUserImplicitly which has effects \[begin\[tx\], commit\[tx\], insert\[users\]\] not declared in this function`
+}
@@ -0,0 +1,60 @@
+package qualified
+
+import "strings"
+
+// Test case: functions are identified by their qualified names, not bare identifiers
+
+type UserRepository struct{}
+
+// dirty: { insert[users] }
+func (r *UserRepository) Create() error {
+	return nil
+}
+
+type OrderRepository struct{}
+
+// dirty: { insert[orders] }
+func (r *OrderRepository) Create() error {
+	return nil
+}
+
+// Valid: only the user repository is called
+// dirty: { insert[users] }
+func CreateUser(users *UserRepository) error {
+	return users.Create()
+}
+
+// Invalid: the order repository's Create has a different effect
+// dirty: { insert[users] }
+func CreateOrder(orders *OrderRepository) error {
+	return orders.Create() // want `function calls \(\*OrderRepository\)\.Create which has effects \[insert\[orders\]\] not declared in this function`
+}
+
+// A local function sharing its name with a method
+// dirty: { delete[users] }
+func Create() error {
+	return nil
+}
+
+// Valid: the method call does not resolve to the local Create function
+// dirty: { insert[users] }
+func CreateViaMethod(users *UserRepository) error {
+	return users.Create()
+}
+
+// dirty: { select[logs] }
+func Join(parts []string) string {
+	return ""
+}
+
+// Valid: strings.Join does not resolve to the local Join function
+// dirty: { }
+func Format(parts []string) string {
+	return strings.Join(parts, ",")
+}
+
+// Invalid: the local Join is still checked
+// dirty: { }
+func FormatLocal(parts []string) string {
+	return Join(parts) // want `function calls Join which has effects \[select\[logs\]\] not declared in this function`
+}
@@ -0,0 +1,51 @@
+package rediseffects
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Test case: cache effects inferred from go-redis commands
+
+const sessionKey = "session:current"
+
+// Valid: reads of the user namespace
+// dirty: { cache_read[user] }
+func CachedUser(ctx context.Context, rdb *redis.Client, id int) (string, error) {
+	return rdb.Get(ctx, fmt.Sprintf("user:%d", id)).Result()
+}
+
+// Invalid: writes to the session namespace
+// dirty: { cache_read[session] }
+func Login(ctx context.Context, rdb *redis.Client, token string) {
+	rdb.Get(ctx, sessionKey)
+	rdb.Set(ctx, "session:"+token, "1", time.Hour) // want `function calls github\.com/redis/go-redis/v9\.cmdable\.Set which has effects \[cache_write\[session\]\] not declared in this function`
+}
+
+// Invalid: deleting keys of several namespaces and reading and deleting at once
+// dirty: { cache_write[user] }
+func Evict(ctx context.Context, rdb *redis.Client, id string) {
+	rdb.Del(ctx, "user:"+id, "profile:"+id)   // want `function calls github\.com/redis/go-redis/v9\.cmdable\.Del which has effects \[cache_write\[profile\], cache_write\[user\]\] not declared in this function`
+	rdb.GetDel(ctx, "user:"+id)               // want `function calls github\.com/redis/go-redis/v9\.cmdable\.GetDel which has effects \[cache_read\[user\], cache_write\[user\]\] not declared in this function`
+	rdb.HSet(ctx, "counters", "evictions", 1) // want `function calls github\.com/redis/go-redis/v9\.cmdable\.HSet which has effects \[cache_write\[counters\]\] not declared in this function`
+}
+
+// Invalid: commands queued in pipelines
+// dirty: { cache_read[user] }
+func Warm(ctx context.Context, rdb *redis.Client, id string) error {
+	pipe := rdb.Pipeline()
+	pipe.Get(ctx, "user:"+id)
+	pipe.Set(ctx, "feed:"+id, "[]", 0) // want `function calls github\.com/redis/go-redis/v9\.Pipeliner\.Set which has effects \[cache_write\[feed\]\] not declared in this function`
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Valid: keys without a known prefix have no inferred effects
+// dirty: { }
+func Lookup(ctx context.Context, rdb *redis.Client, key string) {
+	rdb.Get(ctx, key)
+	rdb.Get(ctx, fmt.Sprintf("%s:profile", key))
+}
@@ -0,0 +1,75 @@
+package sqleffects
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// Test case: database effects inferred from SQL string constants
+
+const userColumns = "id, name, org_id"
+
+const selectUsers = "SELECT " + userColumns + " FROM users"
+
+// Valid: the query only reads users and orgs
+// dirty: { select[users] | select[orgs] }
+func ListUsers(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT u.id FROM users u JOIN orgs o ON o.id = u.org_id WHERE o.name = $1", "acme")
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// Invalid: the insert is not declared
+// dirty: { select[users] }
+func CreateOrder(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO orders (user_id) VALUES ($1)", 1) // want `function calls database/sql\.\(\*Tx\)\.ExecContext which has effects \[insert\[orders\]\] not declared in this function`
+	return err
+}
+
+// Effects of undeclared functions are computed from their queries
+func findUser(db *sql.DB) *sql.Row {
+	return db.QueryRow(selectUsers + " WHERE id = ?")
+}
+
+// Invalid: the constant query reads users
+// dirty: { }
+func ShowUser(db *sql.DB) {
+	findUser(db) // want `function calls findUser which has effects \[select\[users\]\] not declared in this function`
+}
+
+// Invalid: sqlx methods, including those promoted from *sql.DB
+// dirty: { select[users] }
+func Cleanup(ctx context.Context, db *sqlx.DB) error {
+	var names []string
+	if err := db.Select(&names, selectUsers); err != nil {
+		return err
+	}
+	if err := sqlx.Get(db, &names, "SELECT name FROM users WHERE id = 1"); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "DELETE FROM sessions WHERE user_id IN (SELECT id FROM users)") // want `function calls database/sql\.\(\*DB\)\.ExecContext which has effects \[delete\[sessions\], select\[users\]\] not declared in this function`
+	return err
+}
+
+const upsertMember = `
+	INSERT INTO members (id) VALUES ($1)
+	ON CONFLICT (id) DO UPDATE SET updated_at = now()`
+
+// Invalid: pgx queries with an upsert
+// dirty: { insert[members] }
+func AddMember(ctx context.Context, conn *pgx.Conn) error {
+	_, err := conn.Exec(ctx, upsertMember, 1) // want `function calls github\.com/jackc/pgx/v5\.\(\*Conn\)\.Exec which has effects \[insert\[members\], update\[members\]\] not declared in this function`
+	return err
+}
+
+// Valid: queries that are not constants are not inferred
+// dirty: { }
+func Dynamic(db *sql.DB, query string) error {
+	_, err := db.Exec(query)
+	return err
+}
@@ -0,0 +1,62 @@
+package ssacallgraph
+
+// Test case: dynamic calls resolved by the SSA call graph backend
+
+type Notifier interface {
+	// Methods without a contract are resolved to the implementations that reach the call
+	Notify(msg string)
+}
+
+type mailer struct{}
+
+// dirty: { insert[mails] }
+func (mailer) Notify(msg string) {}
+
+type pager struct{}
+
+// dirty: { insert[pages] }
+func (pager) Notify(msg string) {}
+
+// Invalid: only the mailer reaches the call
+// dirty: { }
+func SendMail() {
+	var n Notifier = mailer{}
+	n.Notify("hello") // want `function calls mailer\.Notify which has effects \[insert\[mails\]\] not declared in this function`
+}
+
+// Valid: the pager is never stored in this Notifier
+// dirty: { insert[mails] }
+func SendMailOnly() {
+	var n Notifier = mailer{}
+	n.Notify("hello")
+}
+
+// dirty: { insert[pages] }
+func Page() {
+	var n Notifier = pager{}
+	n.Notify("hello")
+}
+
+// dirty: { delete[users] }
+func deleteUsers() {}
+
+// dirty: { delete[sessions] }
+func deleteSessions() {}
+
+// Invalid: the functions stored in the slice are called in the loop
+// dirty: { delete[users] }
+func Cleanup() {
+	steps := []func(){deleteUsers, deleteSessions}
+	for _, step := range steps {
+		step() // want `function calls deleteSessions which has effects \[delete\[sessions\]\] not declared in this function`
+	}
+}
+
+// Valid: the closure only calls what it captured
+// dirty: { delete[users] }
+func Deferred() {
+	run := func(f func()) func() {
+		return func() { f() }
+	}
+	run(deleteUsers)()
+}
@@ -0,0 +1,28 @@
+package ssafmt
+
+// Test case: the SSA call graph backends on a package importing the standard library
+
+import "fmt"
+
+type Logger interface {
+	Log(msg string)
+}
+
+type fileLogger struct{}
+
+// dirty: { insert[logs] }
+func (fileLogger) Log(msg string) {}
+
+// Invalid: the logger found by the SSA call graph inserts logs
+// dirty: { }
+func Report(code int) {
+	var l Logger = fileLogger{}
+	l.Log(fmt.Sprintf("code %d", code)) // want `function calls fileLogger\.Log which has effects \[insert\[logs\]\] not declared in this function`
+}
+
+// Valid: the effects of fileLogger.Log are declared
+// dirty: { insert[logs] }
+func Print(code int) {
+	var l Logger = fileLogger{}
+	l.Log(fmt.Sprint(code))
+}
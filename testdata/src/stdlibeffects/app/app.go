@@ -0,0 +1,41 @@
+package app // want package:"PackageEffectsFact\\{5 functions\\}"
+
+import (
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Test case: effects of standard library functions from the built-in catalog
+
+// Valid: the catalog declares network for the client
+// dirty: { network }
+func Fetch(client *http.Client, req *http.Request) (*http.Response, error) { // want Fetch:"FunctionEffectsFact\\[network\\]"
+	return client.Do(req)
+}
+
+// Invalid: writing files is io[fs]
+// dirty: { }
+func Save(data []byte) error { // want Save:"FunctionEffectsFact\\[io\\[fs\\]\\]"
+	return os.WriteFile("out.txt", data, 0o600) // want `function calls os\.WriteFile which has effects \[io\[fs\]\] not declared in this function`
+}
+
+// Invalid: running commands is exec
+// dirty: { io[fs] }
+func Build() error { // want Build:"FunctionEffectsFact\\[exec io\\[fs\\]\\]"
+	return exec.Command("go", "build").Run() // want `function calls os/exec\.\(\*Cmd\)\.Run which has effects \[exec\] not declared in this function`
+}
+
+// Invalid: the effect registry overrides time.Now but not time.Since
+// dirty: { }
+func Elapsed() time.Duration { // want Elapsed:"FunctionEffectsFact\\[nondeterminism\\]"
+	start := time.Now()
+	return time.Since(start) // want `function calls time\.Since which has effects \[nondeterminism\] not declared in this function`
+}
+
+// Valid: the effect registry declares os.Remove
+// dirty: { io[tmp] }
+func Cleanup() error { // want Cleanup:"FunctionEffectsFact\\[io\\[tmp\\]\\]"
+	return os.Remove("out.txt")
+}
@@ -0,0 +1,40 @@
+package app // want package:"PackageEffectsFact\\{4 functions\\}" `package calls 3 callees with unknown effects: database/sql\.\(\*DB\)\.QueryContext, strictfacts/db\.Lookup, strings\.ToUpper`
+
+import (
+	"context"
+	"database/sql"
+	"strictfacts/db"
+	"strings"
+)
+
+// Test case: functions of packages with facts are only known by their declarations
+
+// Valid: declared functions and contracts of other packages
+// dirty: { select[users] }
+func Show(s db.Store) { // want Show:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	db.FindUser()
+	s.Load()
+}
+
+// Valid: facts of functions without a declaration are known when their callees are
+// dirty: { select[users] }
+func ShowHelper() { // want ShowHelper:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	db.Helper()
+}
+
+// Invalid: facts of functions calling unknown callees do not make them known
+// dirty: { select[users] }
+func ShowAll() string { // want ShowAll:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	db.Lookup()                  // want `function calls strictfacts/db\.Lookup whose effects are unknown`
+	return strings.ToUpper("ok") // want `function calls strings\.ToUpper whose effects are unknown`
+}
+
+// Invalid: only calls whose effects are inferred are known
+// dirty: { select[users] }
+func Query(ctx context.Context, conn *sql.DB, q string) error { // want Query:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	if _, err := conn.QueryContext(ctx, "SELECT * FROM users"); err != nil {
+		return err
+	}
+	_, err := conn.QueryContext(ctx, q) // want `function calls database/sql\.\(\*DB\)\.QueryContext whose effects are unknown`
+	return err
+}
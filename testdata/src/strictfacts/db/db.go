@@ -0,0 +1,33 @@
+package db
+
+import "strings"
+
+// dirty: { select[users] }
+func FindUser() {}
+
+// Helper has no declaration, but the effects of its callees are all known
+func Helper() {
+	FindUser()
+}
+
+// Lookup has the effects of FindUser, but calls a function with unknown effects
+func Lookup() {
+	FindUser()
+	strings.ToLower("users")
+}
+
+type Store interface {
+	// dirty: { select[users] }
+	Load()
+}
+
+// Repo is embedded by the service package
+type Repo struct{}
+
+// dirty: { select[users] }
+func (r *Repo) Find() {}
+
+// Remove has no declaration, but the effects of its callees are all known
+func (r *Repo) Remove() {
+	r.Find()
+}
@@ -0,0 +1,20 @@
+package indirect // want package:"PackageEffectsFact\\{2 functions\\}"
+
+import "strictfacts/service"
+
+// Test case: functions of packages imported only by an imported package are known
+// with the effects of their facts in every strict mode
+
+// Valid: the effects of the promoted method are declared
+// dirty: { select[users] }
+func Show(s *service.Service) { // want Show:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	s.Find()
+	s.Remove()
+}
+
+// Invalid: the known effects of the promoted methods are checked
+// dirty: { }
+func ShowBroken(s *service.Service) { // want ShowBroken:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	s.Find()   // want `function calls strictfacts/db\.\(\*Repo\)\.Find which has effects \[select\[users\]\] not declared in this function`
+	s.Remove() // want `function calls strictfacts/db\.\(\*Repo\)\.Remove which has effects \[select\[users\]\] not declared in this function`
+}
@@ -0,0 +1,8 @@
+package service
+
+import "strictfacts/db"
+
+// Service embeds the repository so its methods are promoted
+type Service struct {
+	*db.Repo
+}
@@ -0,0 +1,18 @@
+package top // want package:"PackageEffectsFact\\{2 functions\\}" `package calls 1 callees with unknown effects: strictfacts/db\.Lookup`
+
+import "strictfacts/db"
+
+// Test case: functions of other packages whose effects are known in part keep the
+// effects of their facts and get any effect in strict top mode
+
+// Invalid: the declaration covers the effects of the facts only
+// dirty: { select[users] }
+func ShowAll() { // want ShowAll:"FunctionEffectsFact\\[\\* select\\[users\\]\\]"
+	db.Lookup() // want `function calls strictfacts/db\.Lookup which has effects \[\*, select\[users\]\] not declared in this function`
+}
+
+// Valid: functions whose effects are fully known do not get any effect
+// dirty: { select[users] }
+func ShowHelper() { // want ShowHelper:"FunctionEffectsFact\\[select\\[users\\]\\]"
+	db.Helper()
+}
@@ -0,0 +1,14 @@
+package strictgradual
+
+import "strings"
+
+// Test case: packages whose unknown calls are all in functions without a declaration
+// are not reported, so strict mode can be adopted one package at a time
+
+var handlers = map[string]func(){}
+
+// Undeclared functions are not checked
+func Shout(s string) string {
+	handlers["shout"]()
+	return strings.ToUpper(s)
+}
@@ -0,0 +1,20 @@
+package strictregistry // want `package calls 2 callees with unknown effects: hook at strictregistry\.go:12:2, strings\.ToUpper`
+
+import "strings"
+
+// Test case: functions of other packages need an effect registry entry in strict registry mode
+
+var hook func()
+
+// Invalid: strings.ToUpper has neither facts nor a registry entry
+// dirty: { }
+func Shout(s string) string {
+	hook()
+	return strings.ToUpper(s) // want `function calls strings\.ToUpper which has no entry in the effect registry`
+}
+
+// Valid: strings.ToLower is declared in the effect registry
+// dirty: { }
+func Whisper(s string) string {
+	return strings.ToLower(s)
+}
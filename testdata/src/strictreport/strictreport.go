@@ -0,0 +1,44 @@
+package strictreport // want `package calls 4 callees with unknown effects: Notifier\.Notify, fmt\.Println, hook at strictreport\.go:30:2, strings\.ToUpper`
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Test case: calls of unknown callees are reported in strict report mode
+
+type Notifier interface {
+	Notify(msg string)
+}
+
+var hook func()
+
+// dirty: { select[users] }
+func selectUsers() {}
+
+// Invalid: the effects of functions of packages without facts are unknown
+// dirty: { select[users] }
+func ListUsers() {
+	selectUsers()
+	fmt.Println("users") // want `function calls fmt\.Println whose effects are unknown`
+}
+
+// Invalid: calls through an interface without a contract and an unassigned variable
+// dirty: { }
+func Notify(n Notifier) {
+	n.Notify("hello") // want `function calls Notifier\.Notify whose callees are unknown`
+	hook()            // want `function calls hook whose callees are unknown`
+}
+
+// Valid: conversions, builtins and local functions are known
+// dirty: { select[users] }
+func Known(names []string) int {
+	selectUsers()
+	_ = []byte("users")
+	return len(names)
+}
+
+// Undeclared functions are only summarized
+func upper(s string) string {
+	return strings.ToUpper(s)
+}
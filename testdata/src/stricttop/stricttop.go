@@ -0,0 +1,31 @@
+package stricttop // want `package calls 1 callees with unknown effects: strings\.ToUpper`
+
+import "strings"
+
+// Test case: unknown callees have any effect in strict top mode
+
+// dirty: { select[users] }
+func selectUsers() {}
+
+func upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+// Invalid: the unknown effects propagate through the undeclared helper
+// dirty: { select[users] }
+func ShowUser() string {
+	selectUsers()
+	return upper("user") // want `function calls upper which has effects \[\*\] not declared in this function`
+}
+
+// Invalid: the unknown callee is called directly
+// dirty: { }
+func Shout(s string) string {
+	return strings.ToUpper(s) // want `function calls strings\.ToUpper which has effects \[\*\] not declared in this function`
+}
+
+// Invalid: no wildcard covers the unknown effects
+// dirty: { *[*] }
+func ShoutAnything(s string) string {
+	return strings.ToUpper(s) // want `function calls strings\.ToUpper which has effects \[\*\] not declared in this function \(wildcards \[\*\[\*\]\] do not cover \[\*\]\)`
+}
@@ -0,0 +1,59 @@
+package wildcards
+
+// Test case: wildcard labels cover every label they match
+
+// dirty: { select[users] }
+func LoadUser() {}
+
+// dirty: { update[users] }
+func SaveUser() {}
+
+// dirty: { delete[users] }
+func DeleteUser() {}
+
+// dirty: { select[orders] }
+func LoadOrder() {}
+
+// dirty: { network }
+func Ping() {}
+
+// dirty: { network[api] }
+func CallAPI() {}
+
+// Valid: a wildcard operation covers every operation on users
+// dirty: { *[users] }
+func ManageUser() {
+	LoadUser()
+	SaveUser()
+	DeleteUser()
+}
+
+// Valid: a wildcard target covers every target, and labels without a target
+// dirty: { select[*] | network[*] }
+func Dashboard() {
+	LoadUser()
+	LoadOrder()
+	Ping()
+	CallAPI()
+}
+
+// Invalid: the wildcard covers selects only
+// dirty: { select[*] }
+func Cleanup() {
+	LoadUser()
+	DeleteUser() // want `function calls DeleteUser which has effects \[delete\[users\]\] not declared in this function \(wildcards \[select\[\*\]\] do not cover \[delete\[users\]\]\)`
+}
+
+// Invalid: callers must cover the wildcard itself, not one of its labels
+// dirty: { select[users] | update[users] | delete[users] }
+func Admin() {
+	ManageUser() // want `function calls ManageUser which has effects \[\*\[users\], delete\[users\], select\[users\], update\[users\]\] not declared in this function`
+}
+
+// Valid: wildcards cover wildcards they match
+// dirty: { *[*] }
+func Everything() {
+	ManageUser()
+	Dashboard()
+	Cleanup()
+}